@@ -14,6 +14,9 @@ import (
 
 const (
 	defaultAPIServer = "https://api.telegram.org"
+
+	// MaxBatchMessages - max number of message ids per forwardMessages, copyMessages and deleteMessages call
+	MaxBatchMessages = 100
 )
 
 type Response struct {
@@ -42,6 +45,7 @@ func NewBot(token string, opts ...Option) (*Bot, error) {
 		logger:     slog.Default(),
 		apiServer:  defaultAPIServer,
 		httpClient: http.DefaultClient,
+		jsonCodec:  stdJSONCodec{},
 		ctx:        context.Background(),
 	}
 
@@ -73,25 +77,36 @@ func (bot *Bot) buildURL(method string) string {
 
 // Decode response result to target object
 func (bot *Bot) decodeResponse(data []byte, target interface{}) error {
-	response := new(Response)
-	if err := json.Unmarshal(data, response); err != nil {
-		return fmt.Errorf("decode response error: %w", err)
+	if target == nil {
+		// Don't need to decode result
+		target = new(json.RawMessage)
+	}
+
+	// Envelope and result are decoded in a single pass
+	response := responseEnvelope{Result: target}
+	if err := bot.jsonCodec.Unmarshal(data, &response); err != nil {
+		return bot.decodeResponseError(data, err)
 	}
 
 	if !response.Ok {
 		return fmt.Errorf("Error %d (%s)", response.ErrorCode, response.Description)
 	}
 
-	if target == nil {
-		// Don't need to decode result
-		return nil
+	return nil
+}
+
+// decodeResponseError - decode response in two passes to tell a broken response from unexpected result
+func (bot *Bot) decodeResponseError(data []byte, err error) error {
+	response := new(Response)
+	if err := bot.jsonCodec.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("decode response error: %w", err)
 	}
 
-	if err := json.Unmarshal(response.Result, target); err != nil {
-		return fmt.Errorf("decode result error: %w", err)
+	if !response.Ok {
+		return fmt.Errorf("Error %d (%s)", response.ErrorCode, response.Description)
 	}
 
-	return nil
+	return fmt.Errorf("decode result error: %w", err)
 }
 
 // Send GET request to Telegram API
@@ -116,7 +131,7 @@ func (bot *Bot) get(method string, params url.Values, target interface{}) error
 
 // Send POST request to Telegram API
 func (bot *Bot) post(method string, data, target interface{}) error {
-	request, err := newPostRequest(bot.ctx, bot.buildURL(method), data)
+	request, err := newPostRequest(bot.ctx, bot.jsonCodec, bot.buildURL(method), data)
 	if err != nil {
 		return err
 	}
@@ -135,7 +150,17 @@ func (bot *Bot) post(method string, data, target interface{}) error {
 
 // Send POST multipart request to Telegram API
 func (bot *Bot) postMultipart(method string, file *fileField, params url.Values, target interface{}) error {
-	request, err := newMultipartRequest(bot.ctx, bot.buildURL(method), file, params)
+	files := []*fileField{}
+	if file != nil {
+		files = append(files, file)
+	}
+
+	return bot.postMultipartFiles(method, files, params, target)
+}
+
+// Send POST multipart request with several files to Telegram API
+func (bot *Bot) postMultipartFiles(method string, files []*fileField, params url.Values, target interface{}) error {
+	request, err := newMultipartRequest(bot.ctx, bot.buildURL(method), files, params)
 	if err != nil {
 		return err
 	}
@@ -171,29 +196,19 @@ func (bot *Bot) getUpdates(offset uint64, allowedUpdates ...string) ([]Update, e
 	return updates, err
 }
 
-// Start getting updates
+// Start getting updates with long polling
 func (bot *Bot) Start(allowedUpdates ...string) {
-	for {
-		updates, err := bot.getUpdates(bot.offset+1, allowedUpdates...)
-		if err != nil {
-			bot.logger.ErrorContext(bot.ctx, "Get updates error", "error", err)
-			httpErr := HTTPError{}
-			if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusConflict {
-				bot.cancelFunc()
-			}
-		}
+	bot.StartWith(bot.LongPolling(allowedUpdates...))
+}
 
-		for _, update := range updates {
-			bot.updates <- update
-			bot.offset = update.UpdateID
-		}
+// StartWith - feed updates channel from source (long polling, webhook or replayer of saved updates).
+// Channel is closed when the source is exhausted or the bot is stopped.
+func (bot *Bot) StartWith(source UpdateSource) {
+	defer close(bot.updates)
 
-		select {
-		case <-bot.ctx.Done():
-			close(bot.updates)
-			return
-		default:
-		}
+	err := source.Run(bot.ctx, bot.updates)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		bot.logger.ErrorContext(bot.ctx, "Update source error", "error", err)
 	}
 }
 
@@ -275,7 +290,7 @@ func (bot *Bot) GetMe() (*User, error) {
 
 // Raw - send any method and return raw response
 func (bot *Bot) Raw(method string, data any) ([]byte, error) {
-	request, err := newPostRequest(bot.ctx, bot.buildURL(method), data)
+	request, err := newPostRequest(bot.ctx, bot.jsonCodec, bot.buildURL(method), data)
 	if err != nil {
 		return nil, err
 	}
@@ -304,6 +319,91 @@ func (bot *Bot) SendMessage(chatID ChatID, text string, options *SendMessageOpti
 	return message, err
 }
 
+// SendLongMessage - send text longer than MaxMessageLength as several messages.
+// Text is split at paragraph, line or word boundaries without breaking formatting (see SplitText).
+// Plain text with options.Entities, PARSE_MODE_HTML and PARSE_MODE_MARKDOWN_V2 are supported.
+// Reply parameters are applied to the first message and reply markup to the last one.
+// On error the messages sent so far are returned along with the error.
+func (bot *Bot) SendLongMessage(chatID ChatID, text string, options *SendMessageOptions) ([]*Message, error) {
+	opts := SendMessageOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	text, entities, err := parseSplitText(text, opts.ParseMode, opts.Entities)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, ErrEmptyText
+	}
+
+	return bot.sendTextParts(chatID, SplitText(text, entities, MaxMessageLength), opts, nil)
+}
+
+// SendPhotoWithLongCaption - send photo with caption longer than MaxCaptionLength.
+// Caption is split like in SendLongMessage: the first part is sent as photo caption
+// and the rest as text messages following the photo.
+// Reply parameters are applied to the photo and reply markup to the last message.
+// On error the messages sent so far are returned along with the error.
+func (bot *Bot) SendPhotoWithLongCaption(chatID ChatID, photoID string, options *SendPhotoOptions) ([]*Message, error) {
+	opts := SendPhotoOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	caption, entities, err := parseSplitText(opts.Caption, opts.ParseMode, opts.CaptionEntities)
+	if err != nil {
+		return nil, err
+	}
+
+	first, rest := SplitCaption(caption, entities)
+	photoOptions := opts
+	photoOptions.Caption = first.Text
+	photoOptions.ParseMode = PARSE_MODE_DEFAULT
+	photoOptions.CaptionEntities = first.Entities
+	if len(rest) > 0 {
+		photoOptions.ReplyMarkup = nil
+	}
+
+	photo, err := bot.SendPhoto(chatID, photoID, &photoOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return bot.sendTextParts(chatID, rest, SendMessageOptions{
+		BusinessConnectionID: opts.BusinessConnectionID,
+		DisableNotification:  opts.DisableNotification,
+		ProtectContent:       opts.ProtectContent,
+		ReplyMarkup:          opts.ReplyMarkup,
+	}, []*Message{photo})
+}
+
+// sendTextParts - send parts as messages, append them to sent and return it
+func (bot *Bot) sendTextParts(chatID ChatID, parts []TextPart, opts SendMessageOptions, sent []*Message) ([]*Message, error) {
+	messages := append(make([]*Message, 0, len(sent)+len(parts)), sent...)
+	for i, part := range parts {
+		partOptions := opts
+		partOptions.ParseMode = PARSE_MODE_DEFAULT
+		partOptions.Entities = part.Entities
+		if i > 0 {
+			partOptions.ReplyParameters = nil
+			partOptions.ReplyToMessageID = 0
+		}
+		if i < len(parts)-1 {
+			partOptions.ReplyMarkup = nil
+		}
+
+		message, err := bot.SendMessage(chatID, part.Text, &partOptions)
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
 // Send exists photo by file_id
 func (bot *Bot) SendPhoto(chatID ChatID, photoID string, options *SendPhotoOptions) (*Message, error) {
 	params := newSendPhotoParams(chatID, photoID, options)
@@ -317,7 +417,7 @@ func (bot *Bot) SendPhoto(chatID ChatID, photoID string, options *SendPhotoOptio
 // Send photo file
 func (bot *Bot) SendPhotoFile(chatID ChatID, file io.Reader, fileName string, options *SendPhotoOptions) (*Message, error) {
 	params := newSendPhotoParams(chatID, "", options)
-	values, err := structToValues(params)
+	values, err := structToValues(bot.jsonCodec, params)
 	if err != nil {
 		return nil, err
 	}
@@ -347,19 +447,9 @@ func (bot *Bot) SendAudio(chatID ChatID, audioID string, options *SendAudioOptio
 // Send audio file
 func (bot *Bot) SendAudioFile(chatID ChatID, file io.Reader, fileName string, options *SendAudioOptions) (*Message, error) {
 	params := newSendAudioParams(chatID, "", options)
-	values, err := structToValues(params)
-	if err != nil {
-		return nil, err
-	}
-
-	f := &fileField{
-		Source:    file,
-		Fieldname: "audio",
-		Filename:  fileName,
-	}
 
 	message := new(Message)
-	err = bot.postMultipart("sendAudio", f, values, message)
+	err := bot.postFileWithThumbnail("sendAudio", params, NewInputFile("audio", file, fileName), &params.Thumbnail, params.ThumbnailFile, message)
 
 	return message, err
 }
@@ -377,19 +467,9 @@ func (bot *Bot) SendDocument(chatID ChatID, documentID string, options *SendDocu
 // Send file
 func (bot *Bot) SendDocumentFile(chatID ChatID, file io.Reader, fileName string, options *SendDocumentOptions) (*Message, error) {
 	params := newSendDocumentParams(chatID, "", options)
-	values, err := structToValues(params)
-	if err != nil {
-		return nil, err
-	}
-
-	f := &fileField{
-		Source:    file,
-		Fieldname: "document",
-		Filename:  fileName,
-	}
 
 	message := new(Message)
-	err = bot.postMultipart("sendDocument", f, values, message)
+	err := bot.postFileWithThumbnail("sendDocument", params, NewInputFile("document", file, fileName), &params.Thumbnail, params.ThumbnailFile, message)
 
 	return message, err
 }
@@ -407,7 +487,7 @@ func (bot *Bot) SendSticker(chatID ChatID, stickerID string, options *SendSticke
 // Send .webp sticker file
 func (bot *Bot) SendStickerFile(chatID ChatID, file io.Reader, fileName string, options *SendStickerOptions) (*Message, error) {
 	params := newSendStickerParams(chatID, "", options)
-	values, err := structToValues(params)
+	values, err := structToValues(bot.jsonCodec, params)
 	if err != nil {
 		return nil, err
 	}
@@ -437,19 +517,29 @@ func (bot *Bot) SendVideo(chatID ChatID, videoID string, options *SendVideoOptio
 // Use this method to send video files, Telegram clients support mp4 videos (other formats may be sent as Document).
 func (bot *Bot) SendVideoFile(chatID ChatID, file io.Reader, fileName string, options *SendVideoOptions) (*Message, error) {
 	params := newSendVideoParams(chatID, "", options)
-	values, err := structToValues(params)
-	if err != nil {
-		return nil, err
-	}
 
-	f := &fileField{
-		Source:    file,
-		Fieldname: "video",
-		Filename:  fileName,
-	}
+	message := new(Message)
+	err := bot.postFileWithThumbnail("sendVideo", params, NewInputFile("video", file, fileName), &params.Thumbnail, params.ThumbnailFile, message)
+
+	return message, err
+}
+
+// Send exists animation by file_id
+func (bot *Bot) SendAnimation(chatID ChatID, animationID string, options *SendAnimationOptions) (*Message, error) {
+	params := newSendAnimationParams(chatID, animationID, options)
+
+	message := new(Message)
+	err := bot.post("sendAnimation", params, message)
+
+	return message, err
+}
+
+// Use this method to send animation files (GIF or H.264/MPEG-4 AVC video without sound).
+func (bot *Bot) SendAnimationFile(chatID ChatID, file io.Reader, fileName string, options *SendAnimationOptions) (*Message, error) {
+	params := newSendAnimationParams(chatID, "", options)
 
 	message := new(Message)
-	err = bot.postMultipart("sendVideo", f, values, message)
+	err := bot.postFileWithThumbnail("sendAnimation", params, NewInputFile("animation", file, fileName), &params.Thumbnail, params.ThumbnailFile, message)
 
 	return message, err
 }
@@ -469,7 +559,7 @@ func (bot *Bot) SendVoice(chatID ChatID, voiceID string, options *SendVoiceOptio
 // For this to work, your audio must be in an .ogg file encoded with OPUS (other formats may be sent as Audio or Document).
 func (bot *Bot) SendVoiceFile(chatID ChatID, file io.Reader, fileName string, options *SendVoiceOptions) (*Message, error) {
 	params := newSendVoiceParams(chatID, "", options)
-	values, err := structToValues(params)
+	values, err := structToValues(bot.jsonCodec, params)
 	if err != nil {
 		return nil, err
 	}
@@ -499,19 +589,9 @@ func (bot *Bot) SendVideoNote(chatID ChatID, videoNoteID string, options *SendVi
 // Use this method to send video messages
 func (bot *Bot) SendVideoNoteFile(chatID ChatID, file io.Reader, fileName string, options *SendVideoNoteOptions) (*Message, error) {
 	params := newSendVideoNoteParams(chatID, "", options)
-	values, err := structToValues(params)
-	if err != nil {
-		return nil, err
-	}
-
-	f := &fileField{
-		Source:    file,
-		Fieldname: "video_note",
-		Filename:  fileName,
-	}
 
 	message := new(Message)
-	err = bot.postMultipart("sendVideoNote", f, values, message)
+	err := bot.postFileWithThumbnail("sendVideoNote", params, NewInputFile("video_note", file, fileName), &params.Thumbnail, params.ThumbnailFile, message)
 
 	return message, err
 }
@@ -526,6 +606,43 @@ func (bot *Bot) SendLocation(chatID ChatID, latitude, longitude float64, options
 	return message, err
 }
 
+// Use this method to edit live location messages.
+// A location can be edited until its live_period expires or editing is explicitly disabled by a call to StopMessageLiveLocation.
+func (bot *Bot) EditMessageLiveLocation(chatID ChatID, messageID int64, inlineMessageID string, latitude, longitude float64, options *EditMessageLiveLocationOptions) (*Message, error) {
+	params := editMessageLiveLocationParams{
+		ChatID:          chatID,
+		MessageID:       messageID,
+		InlineMessageID: inlineMessageID,
+		Latitude:        latitude,
+		Longitude:       longitude,
+	}
+	if options != nil {
+		params.EditMessageLiveLocationOptions = *options
+	}
+
+	message := new(Message)
+	err := bot.post("editMessageLiveLocation", params, message)
+
+	return message, err
+}
+
+// Use this method to stop updating a live location message before live_period expires.
+func (bot *Bot) StopMessageLiveLocation(chatID ChatID, messageID int64, inlineMessageID string, options *StopMessageLiveLocationOptions) (*Message, error) {
+	params := stopMessageLiveLocationParams{
+		ChatID:          chatID,
+		MessageID:       messageID,
+		InlineMessageID: inlineMessageID,
+	}
+	if options != nil {
+		params.StopMessageLiveLocationOptions = *options
+	}
+
+	message := new(Message)
+	err := bot.post("stopMessageLiveLocation", params, message)
+
+	return message, err
+}
+
 // Use this method to send information about a venue
 func (bot *Bot) SendVenue(chatID ChatID, latitude, longitude float64, title, address string, options *SendVenueOptions) (*Message, error) {
 	params := newSendVenueParams(chatID, latitude, longitude, title, address, options)
@@ -546,6 +663,70 @@ func (bot *Bot) SendContact(chatID ChatID, phoneNumber, firstName, lastName stri
 	return message, err
 }
 
+// Use this method to send an animated emoji that will display a random value.
+// Empty emoji means DICE_EMOJI_DICE.
+func (bot *Bot) SendDice(chatID ChatID, emoji DiceEmoji, options *SendDiceOptions) (*Message, error) {
+	params := sendDiceParams{
+		ChatID: chatID,
+		Emoji:  emoji,
+	}
+	if options != nil {
+		params.SendDiceOptions = *options
+	}
+
+	message := new(Message)
+	err := bot.post("sendDice", params, message)
+
+	return message, err
+}
+
+// Use this method to send paid media by file_id or HTTP URL.
+func (bot *Bot) SendPaidMedia(chatID ChatID, starCount int, media []InputPaidMedia, options *SendPaidMediaOptions) (*Message, error) {
+	params := newSendPaidMediaParams(chatID, starCount, media, options)
+
+	message := new(Message)
+	err := bot.post("sendPaidMedia", params, message)
+
+	return message, err
+}
+
+// Send paid media files.
+// Media and thumbnails reference uploaded files as "attach://<name>" (see InputFile.Attach),
+// file_id and HTTP URL can be mixed with uploaded files.
+func (bot *Bot) SendPaidMediaFiles(chatID ChatID, starCount int, media []InputPaidMedia, files []InputFile, options *SendPaidMediaOptions) (*Message, error) {
+	params := newSendPaidMediaParams(chatID, starCount, media, options)
+
+	message := new(Message)
+	err := bot.postInputFiles("sendPaidMedia", params, files, message)
+
+	return message, err
+}
+
+// Use this method to send a native poll.
+func (bot *Bot) SendPoll(chatID ChatID, question string, pollOptions []InputPollOption, options *SendPollOptions) (*Message, error) {
+	params := newSendPollParams(chatID, question, pollOptions, options)
+
+	message := new(Message)
+	err := bot.post("sendPoll", params, message)
+
+	return message, err
+}
+
+// Use this method to stop a poll which was sent by the bot.
+// On success, the stopped Poll is returned.
+func (bot *Bot) StopPoll(chatID ChatID, messageID int64, replyMarkup ReplyMarkup) (*Poll, error) {
+	params := stopPollParams{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		ReplyMarkup: replyMarkup,
+	}
+
+	poll := new(Poll)
+	err := bot.post("stopPoll", params, poll)
+
+	return poll, err
+}
+
 // Use this method to forward messages of any kind.
 func (bot *Bot) ForwardMessage(chatID, fromChatID ChatID, messageID int64, disableNotification bool) (*Message, error) {
 	params := map[string]interface{}{
@@ -561,6 +742,74 @@ func (bot *Bot) ForwardMessage(chatID, fromChatID ChatID, messageID int64, disab
 	return message, err
 }
 
+// Use this method to copy messages of any kind.
+// The method is analogous to ForwardMessage, but the copied message doesn't have a link to the original message.
+func (bot *Bot) CopyMessage(chatID, fromChatID ChatID, messageID int64, options *CopyMessageOptions) (*MessageID, error) {
+	params := copyMessageParams{
+		ChatID:     chatID,
+		FromChatID: fromChatID,
+		MessageID:  messageID,
+	}
+	if options != nil {
+		params.CopyMessageOptions = *options
+	}
+
+	result := new(MessageID)
+	err := bot.post("copyMessage", params, result)
+
+	return result, err
+}
+
+// Use this method to forward multiple messages of any kind.
+// Message ids are sorted and deduplicated, batches larger than MaxBatchMessages are sent in several requests.
+// On error the ids of already forwarded messages are returned along with the error.
+func (bot *Bot) ForwardMessages(chatID, fromChatID ChatID, messageIDs []int64, options *ForwardMessagesOptions) ([]MessageID, error) {
+	result := []MessageID{}
+	for _, chunk := range SplitMessageIDs(messageIDs, MaxBatchMessages) {
+		params := forwardMessagesParams{
+			ChatID:     chatID,
+			FromChatID: fromChatID,
+			MessageIDs: chunk,
+		}
+		if options != nil {
+			params.ForwardMessagesOptions = *options
+		}
+
+		ids := []MessageID{}
+		if err := bot.post("forwardMessages", params, &ids); err != nil {
+			return result, err
+		}
+		result = append(result, ids...)
+	}
+
+	return result, nil
+}
+
+// Use this method to copy multiple messages of any kind.
+// Message ids are sorted and deduplicated, batches larger than MaxBatchMessages are sent in several requests.
+// On error the ids of already copied messages are returned along with the error.
+func (bot *Bot) CopyMessages(chatID, fromChatID ChatID, messageIDs []int64, options *CopyMessagesOptions) ([]MessageID, error) {
+	result := []MessageID{}
+	for _, chunk := range SplitMessageIDs(messageIDs, MaxBatchMessages) {
+		params := copyMessagesParams{
+			ChatID:     chatID,
+			FromChatID: fromChatID,
+			MessageIDs: chunk,
+		}
+		if options != nil {
+			params.CopyMessagesOptions = *options
+		}
+
+		ids := []MessageID{}
+		if err := bot.post("copyMessages", params, &ids); err != nil {
+			return result, err
+		}
+		result = append(result, ids...)
+	}
+
+	return result, nil
+}
+
 // Use this method when you need to tell the user that something is happening on the bot's side.
 // The status is set for 5 seconds or less (when a message arrives from your bot, Telegram clients clear its typing status).
 func (bot *Bot) SendChatAction(chatID ChatID, action ChatAction) error {
@@ -660,6 +909,73 @@ func (bot *Bot) EditMessageReplyMarkup(chatID ChatID, messageID int64, inlineMes
 	return message, err
 }
 
+// Use this method to edit animation, audio, document, photo, or video messages.
+// Media can be passed by file_id or HTTP URL.
+func (bot *Bot) EditMessageMedia(chatID ChatID, messageID int64, inlineMessageID string, media InputMedia, replyMarkup ReplyMarkup) (*Message, error) {
+	params := editMessageMediaParams{
+		ChatID:          chatID,
+		MessageID:       messageID,
+		InlineMessageID: inlineMessageID,
+		Media:           media,
+		ReplyMarkup:     replyMarkup,
+	}
+
+	message := new(Message)
+	err := bot.post("editMessageMedia", params, message)
+
+	return message, err
+}
+
+// Edit message media with uploaded file.
+// Media and its thumbnail reference uploaded files as "attach://<name>" (see InputFile.Attach).
+// A new file can't be uploaded when editing inline messages.
+func (bot *Bot) EditMessageMediaFiles(chatID ChatID, messageID int64, media InputMedia, files []InputFile, replyMarkup ReplyMarkup) (*Message, error) {
+	params := editMessageMediaParams{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		Media:       media,
+		ReplyMarkup: replyMarkup,
+	}
+
+	message := new(Message)
+	err := bot.postInputFiles("editMessageMedia", params, files, message)
+
+	return message, err
+}
+
+// postFileWithThumbnail - upload file with optional thumbnail, thumbnail param is set to the thumbnail reference
+func (bot *Bot) postFileWithThumbnail(method string, params interface{}, file InputFile, thumbnailParam *string, thumbnail *InputFile, target interface{}) error {
+	files := []InputFile{file}
+	if thumbnail != nil {
+		*thumbnailParam = thumbnail.Attach()
+		files = append(files, *thumbnail)
+	}
+
+	return bot.postInputFiles(method, params, files, target)
+}
+
+// postInputFiles - send params with files attached by name as multipart request
+func (bot *Bot) postInputFiles(method string, params interface{}, files []InputFile, target interface{}) error {
+	values, err := structToValues(bot.jsonCodec, params)
+	if err != nil {
+		return err
+	}
+
+	fields := make([]*fileField, 0, len(files))
+	for _, file := range files {
+		if file.Name == "" || values.Has(file.Name) {
+			return fmt.Errorf("invalid input file name %q", file.Name)
+		}
+		fields = append(fields, &fileField{
+			Source:    file.Source,
+			Fieldname: file.Name,
+			Filename:  file.FileName,
+		})
+	}
+
+	return bot.postMultipartFiles(method, fields, values, target)
+}
+
 // Use this method to delete a message.
 // A message can only be deleted if it was sent less than 48 hours ago.
 // Any such recently sent outgoing message may be deleted.
@@ -677,6 +993,40 @@ func (bot *Bot) DeleteMessage(chatID ChatID, messageID int64) (bool, error) {
 	return success, err
 }
 
+// Use this method to delete multiple messages simultaneously.
+// Message ids are sorted and deduplicated, batches larger than MaxBatchMessages are sent in several requests.
+// If some of the specified messages can't be found, they are skipped.
+func (bot *Bot) DeleteMessages(chatID ChatID, messageIDs []int64) error {
+	for _, chunk := range SplitMessageIDs(messageIDs, MaxBatchMessages) {
+		params := map[string]interface{}{
+			"chat_id":     chatID,
+			"message_ids": chunk,
+		}
+
+		if err := bot.post("deleteMessages", params, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Use this method to change the chosen reactions on a message.
+// Empty reactions list removes all bot reactions.
+func (bot *Bot) SetMessageReaction(chatID ChatID, messageID int64, reactions []ReactionType, isBig bool) error {
+	params := setMessageReactionParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+		Reaction:  reactions,
+		IsBig:     isBig,
+	}
+	if params.Reaction == nil {
+		params.Reaction = []ReactionType{}
+	}
+
+	return bot.post("setMessageReaction", params, nil)
+}
+
 // Use this method to send answers to an inline query.
 // No more than 50 results per query are allowed.
 func (bot *Bot) AnswerInlineQuery(inlineQueryID string, results InlineQueryResults, options *AnswerInlineQueryOptions) error {
@@ -691,6 +1041,36 @@ func (bot *Bot) AnswerInlineQuery(inlineQueryID string, results InlineQueryResul
 	return bot.post("answerInlineQuery", params, nil)
 }
 
+// Use this method to set the result of an interaction with a Web App
+// and send a corresponding message on behalf of the user to the chat from which the query originated.
+func (bot *Bot) AnswerWebAppQuery(webAppQueryID string, result InlineQueryResult) (*SentWebAppMessage, error) {
+	params := answerWebAppQueryParams{
+		WebAppQueryID: webAppQueryID,
+		Result:        result,
+	}
+
+	message := new(SentWebAppMessage)
+	err := bot.post("answerWebAppQuery", params, message)
+
+	return message, err
+}
+
+// Use this method to store a message that can be sent by a user of a Mini App.
+func (bot *Bot) SavePreparedInlineMessage(userID int64, result InlineQueryResult, options *SavePreparedInlineMessageOptions) (*PreparedInlineMessage, error) {
+	params := savePreparedInlineMessageParams{
+		UserID: userID,
+		Result: result,
+	}
+	if options != nil {
+		params.SavePreparedInlineMessageOptions = *options
+	}
+
+	message := new(PreparedInlineMessage)
+	err := bot.post("savePreparedInlineMessage", params, message)
+
+	return message, err
+}
+
 // Use this method to kick a user from a group or a supergroup.
 // In the case of supergroups, the user will not be able to return to the group on their own using invite links, etc., unless unbanned first.
 // The bot must be an administrator in the group for this to work.
@@ -819,7 +1199,7 @@ func (bot *Bot) SetGameScore(userID int64, score int, options *SetGameScoreOptio
 // Use this method to get data for high score tables.
 // Will return the score of the specified user and several of his neighbors in a game.
 func (bot *Bot) GetGameHighScores(userID int64, options *GetGameHighScoresOptions) ([]GameHighScore, error) {
-	params, err := structToValues(options)
+	params, err := structToValues(bot.jsonCodec, options)
 	if err != nil {
 		return nil, err
 	}
@@ -3,12 +3,14 @@ package micha
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -33,6 +35,7 @@ func (s *BotTestSuite) SetupSuite() {
 			logger:     slog.Default(),
 			apiServer:  defaultAPIServer,
 			httpClient: http.DefaultClient,
+			jsonCodec:  stdJSONCodec{},
 		},
 	}
 	s.bot.ctx, s.bot.cancelFunc = context.WithCancel(context.Background())
@@ -261,7 +264,7 @@ func (s *BotTestSuite) TestGetWebhookInfo() {
 	s.Require().Equal("someurl", webhookInfo.URL)
 	s.True(webhookInfo.HasCustomCertificate)
 	s.Require().Equal(33, webhookInfo.PendingUpdateCount)
-	s.Require().Equal(uint64(1480190406), webhookInfo.LastErrorDate)
+	s.Require().Equal(UnixTime(1480190406), webhookInfo.LastErrorDate)
 	s.Require().Equal("No way", webhookInfo.LastErrorMessage)
 	s.Require().Equal(4, webhookInfo.MaxConnections)
 	s.Require().Equal([]string{"message", "callback_query"}, webhookInfo.AllowedUpdates)
@@ -388,7 +391,7 @@ func (s *BotTestSuite) TestDownloadFileURL() {
 }
 
 func (s *BotTestSuite) TestSendPhoto() {
-	request := `{"chat_id":"111","photo":"35f9f497a879436fbb6e682f6dd75986","caption":"test caption","reply_to_message_id":143}`
+	request := `{"chat_id":111,"photo":"35f9f497a879436fbb6e682f6dd75986","caption":"test caption","reply_to_message_id":143}`
 	s.registerRequestCheck("sendPhoto", request)
 
 	message, err := s.bot.SendPhoto("111", "35f9f497a879436fbb6e682f6dd75986", &SendPhotoOptions{
@@ -422,7 +425,7 @@ func (s *BotTestSuite) TestSendPhotoFile() {
 }
 
 func (s *BotTestSuite) TestSendAudio() {
-	request := `{"chat_id":"123","audio":"061c2810391f44f6beffa3ee8a7e5af4","duration":36,"performer":"John Doe","title":"Single","reply_to_message_id":143}`
+	request := `{"chat_id":123,"audio":"061c2810391f44f6beffa3ee8a7e5af4","duration":36,"performer":"John Doe","title":"Single","reply_to_message_id":143}`
 	s.registerRequestCheck("sendAudio", request)
 
 	message, err := s.bot.SendAudio("123", "061c2810391f44f6beffa3ee8a7e5af4", &SendAudioOptions{
@@ -462,7 +465,7 @@ func (s *BotTestSuite) TestSendAudioFile() {
 }
 
 func (s *BotTestSuite) TestSendDocument() {
-	request := `{"chat_id":"124","document":"efd8d08958894a6781873b9830634483","caption":"document caption","reply_to_message_id":144}`
+	request := `{"chat_id":124,"document":"efd8d08958894a6781873b9830634483","caption":"document caption","reply_to_message_id":144}`
 	s.registerRequestCheck("sendDocument", request)
 
 	message, err := s.bot.SendDocument("124", "efd8d08958894a6781873b9830634483", &SendDocumentOptions{
@@ -496,7 +499,7 @@ func (s *BotTestSuite) TestSendDocumentFile() {
 }
 
 func (s *BotTestSuite) TestSendSticker() {
-	request := `{"chat_id":"125","sticker":"070114a7fa964322acb3d65e6e36eb2b","reply_to_message_id":145}`
+	request := `{"chat_id":125,"sticker":"070114a7fa964322acb3d65e6e36eb2b","reply_to_message_id":145}`
 	s.registerRequestCheck("sendSticker", request)
 
 	message, err := s.bot.SendSticker("125", "070114a7fa964322acb3d65e6e36eb2b", &SendStickerOptions{
@@ -526,7 +529,7 @@ func (s *BotTestSuite) TestSendStickerFile() {
 }
 
 func (s *BotTestSuite) TestSendVideo() {
-	request := `{"chat_id":"126","video":"b169f647c020405b8c9035cf3f315ff0","duration":22,"width":320,"height":240,"caption":"video caption","reply_to_message_id":146}`
+	request := `{"chat_id":126,"video":"b169f647c020405b8c9035cf3f315ff0","duration":22,"width":320,"height":240,"caption":"video caption","reply_to_message_id":146}`
 	s.registerRequestCheck("sendVideo", request)
 
 	message, err := s.bot.SendVideo("126", "b169f647c020405b8c9035cf3f315ff0", &SendVideoOptions{
@@ -568,8 +571,84 @@ func (s *BotTestSuite) TestSendVideoFile() {
 	s.Require().NotNil(message)
 }
 
+func (s *BotTestSuite) TestSendVideoFileThumbnail() {
+	httpmock.RegisterResponder("POST", s.bot.buildURL("sendVideo"), func(request *http.Request) (*http.Response, error) {
+		if err := request.ParseMultipartForm(1024); err != nil {
+			return nil, err
+		}
+
+		form := request.MultipartForm
+		s.Require().Equal([]string{"789"}, form.Value["chat_id"])
+		s.Require().Equal([]string{"attach://cover"}, form.Value["thumbnail"])
+
+		for name, content := range map[string]string{"video": "video data", "cover": "thumb data"} {
+			s.Require().Equal(1, len(form.File[name]))
+			file, err := form.File[name][0].Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+			s.Require().Equal(content, string(data))
+		}
+
+		return httpmock.NewStringResponse(200, `{"ok":true, "result": {}}`), nil
+	})
+
+	thumbnail := NewInputFile("cover", strings.NewReader("thumb data"), "cover.jpg")
+	_, err := s.bot.SendVideoFile("789", strings.NewReader("video data"), "cats.mp4", &SendVideoOptions{
+		ThumbnailFile: &thumbnail,
+	})
+	s.Require().Nil(err)
+
+	// Deprecated thumb is sent as thumbnail
+	s.registerRequestCheck("sendDocument", `{"chat_id":124,"document":"doc","thumbnail":"attach://old"}`)
+	_, err = s.bot.SendDocument("124", "doc", &SendDocumentOptions{Thumb: "attach://old"})
+	s.Require().Nil(err)
+}
+
+func (s *BotTestSuite) TestSendAnimation() {
+	request := `{"chat_id":126,"animation":"a7f9","width":320,"caption":"gif","has_spoiler":true}`
+	s.registerRequestCheck("sendAnimation", request)
+
+	message, err := s.bot.SendAnimation("126", "a7f9", &SendAnimationOptions{
+		Width:      320,
+		Caption:    "gif",
+		HasSpoiler: true,
+	})
+
+	s.Require().Nil(err)
+	s.Require().NotNil(message)
+}
+
+func (s *BotTestSuite) TestSendAnimationFile() {
+	params := url.Values{
+		"chat_id":  {"789"},
+		"duration": {"3"},
+		"caption":  {"dancing cat"},
+	}
+	data := bytes.NewBufferString("gif data")
+	file := fileField{
+		Source:    bytes.NewBufferString("gif data"),
+		Fieldname: "animation",
+		Filename:  "cat.gif",
+	}
+	s.registeMultipartrRequestCheck("sendAnimation", params, file)
+
+	message, err := s.bot.SendAnimationFile("789", data, "cat.gif", &SendAnimationOptions{
+		Duration: 3,
+		Caption:  "dancing cat",
+	})
+
+	s.Require().Nil(err)
+	s.Require().NotNil(message)
+}
+
 func (s *BotTestSuite) TestSendVoice() {
-	request := `{"chat_id":"127","voice":"75ac50947bc34a3ea2efdca5000d9ad5","duration":56,"reply_to_message_id":147}`
+	request := `{"chat_id":127,"voice":"75ac50947bc34a3ea2efdca5000d9ad5","duration":56,"reply_to_message_id":147}`
 	s.registerRequestCheck("sendVoice", request)
 
 	message, err := s.bot.SendVoice("127", "75ac50947bc34a3ea2efdca5000d9ad5", &SendVoiceOptions{
@@ -605,7 +684,7 @@ func (s *BotTestSuite) TestSendVoiceFile() {
 func (s *BotTestSuite) TestSendVideoNote() {
 	// Test without options
 	s.registerResultWithRequestCheck("sendVideoNote", "{}", `{
-		"chat_id": "123",
+		"chat_id": 123,
 		"video_note": "837y7w6gdf6sd"
 	}`)
 
@@ -617,7 +696,7 @@ func (s *BotTestSuite) TestSendVideoNote() {
 
 	// Test with options
 	s.registerResultWithRequestCheck("sendVideoNote", "{}", `{
-		"chat_id": "123",
+		"chat_id": 123,
 		"video_note": "837y7w6gdf6sd",
 		"duration": 22,
 		"length": 133,
@@ -659,7 +738,7 @@ func (s *BotTestSuite) TestSendVideoNoteFile() {
 }
 
 func (s *BotTestSuite) TestSendLocation() {
-	request := `{"chat_id":"128","latitude":22.532434,"longitude":-44.8243324,"reply_to_message_id":148}`
+	request := `{"chat_id":128,"latitude":22.532434,"longitude":-44.8243324,"reply_to_message_id":148}`
 	s.registerRequestCheck("sendLocation", request)
 
 	message, err := s.bot.SendLocation("128", 22.532434, -44.8243324, &SendLocationOptions{
@@ -670,8 +749,100 @@ func (s *BotTestSuite) TestSendLocation() {
 	s.Require().NotNil(message)
 }
 
+func (s *BotTestSuite) TestEditMessageLiveLocation() {
+	request := `{"chat_id":128,"message_id":5,"latitude":22.5,"longitude":-44.8,"heading":90}`
+	s.registerRequestCheck("editMessageLiveLocation", request)
+
+	_, err := s.bot.EditMessageLiveLocation("128", 5, "", 22.5, -44.8, &EditMessageLiveLocationOptions{
+		Heading: 90,
+	})
+	s.Require().Nil(err)
+}
+
+func (s *BotTestSuite) TestStopMessageLiveLocation() {
+	request := `{"inline_message_id":"ilm"}`
+	s.registerRequestCheck("stopMessageLiveLocation", request)
+
+	_, err := s.bot.StopMessageLiveLocation("", 0, "ilm", nil)
+	s.Require().Nil(err)
+
+	httpmock.Reset()
+	request = `{"chat_id":128,"message_id":5,"business_connection_id":"bc","reply_markup":{"force_reply":true}}`
+	s.registerRequestCheck("stopMessageLiveLocation", request)
+
+	_, err = s.bot.StopMessageLiveLocation("128", 5, "", &StopMessageLiveLocationOptions{
+		BusinessConnectionID: "bc",
+		ReplyMarkup:          ForceReply{ForceReply: true},
+	})
+	s.Require().Nil(err)
+}
+
+func (s *BotTestSuite) TestSendDice() {
+	request := `{"chat_id":128,"emoji":"🎯","disable_notification":true}`
+	s.registerResultWithRequestCheck("sendDice", `{"message_id":1,"dice":{"emoji":"🎯","value":6}}`, request)
+
+	message, err := s.bot.SendDice("128", DICE_EMOJI_DARTS, &SendDiceOptions{
+		DisableNotification: true,
+	})
+	s.Require().Nil(err)
+	s.Require().Equal(&Dice{Emoji: DICE_EMOJI_DARTS, Value: 6}, message.Dice)
+}
+
+func (s *BotTestSuite) TestSendPaidMedia() {
+	request := `{"chat_id":128,"star_count":10,"media":[{"type":"photo","media":"ph1"},{"type":"video","media":"https://example.com/v.mp4","width":640}],"payload":"pl"}`
+	s.registerRequestCheck("sendPaidMedia", request)
+
+	_, err := s.bot.SendPaidMedia("128", 10, []InputPaidMedia{
+		{Type: INPUT_MEDIA_TYPE_PHOTO, Media: "ph1"},
+		{Type: INPUT_MEDIA_TYPE_VIDEO, Media: "https://example.com/v.mp4", Width: 640},
+	}, &SendPaidMediaOptions{
+		Payload: "pl",
+	})
+	s.Require().Nil(err)
+}
+
+func (s *BotTestSuite) TestSendPaidMediaFiles() {
+	httpmock.RegisterResponder("POST", s.bot.buildURL("sendPaidMedia"), func(request *http.Request) (*http.Response, error) {
+		if err := request.ParseMultipartForm(1024); err != nil {
+			return nil, err
+		}
+
+		form := request.MultipartForm
+		s.Require().Equal([]string{"128"}, form.Value["chat_id"])
+		s.Require().Equal([]string{"10"}, form.Value["star_count"])
+		s.JSONEq(`[{"type":"photo","media":"ph1"},{"type":"video","media":"attach://video","thumbnail":"attach://thumb"}]`, form.Value["media"][0])
+
+		for name, content := range map[string]string{"video": "video data", "thumb": "thumb data"} {
+			s.Require().Equal(1, len(form.File[name]))
+			file, err := form.File[name][0].Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+			s.Require().Equal(content, string(data))
+		}
+
+		return httpmock.NewStringResponse(200, `{"ok":true, "result": {}}`), nil
+	})
+
+	video := NewInputFile("video", strings.NewReader("video data"), "video.mp4")
+	thumb := NewInputFile("thumb", strings.NewReader("thumb data"), "thumb.jpg")
+	paidVideo := NewInputPaidMediaVideo(video.Attach())
+	paidVideo.Thumbnail = thumb.Attach()
+
+	_, err := s.bot.SendPaidMediaFiles("128", 10, []InputPaidMedia{NewInputPaidMediaPhoto("ph1"), paidVideo}, []InputFile{video, thumb}, nil)
+	s.Require().Nil(err)
+
+	_, err = s.bot.SendPaidMediaFiles("128", 10, []InputPaidMedia{NewInputPaidMediaPhoto("attach://media")}, []InputFile{NewInputFile("media", strings.NewReader(""), "x")}, nil)
+	s.Require().EqualError(err, `invalid input file name "media"`)
+}
+
 func (s *BotTestSuite) TestSendVenue() {
-	request := `{"chat_id":"129","latitude":22.532434,"longitude":-44.8243324,"title":"Kremlin","address":"Red Square 1","foursquare_id":"1","reply_to_message_id":149}`
+	request := `{"chat_id":129,"latitude":22.532434,"longitude":-44.8243324,"title":"Kremlin","address":"Red Square 1","foursquare_id":"1","reply_to_message_id":149}`
 	s.registerRequestCheck("sendVenue", request)
 
 	message, err := s.bot.SendVenue("129", 22.532434, -44.8243324, "Kremlin", "Red Square 1", &SendVenueOptions{
@@ -684,7 +855,7 @@ func (s *BotTestSuite) TestSendVenue() {
 }
 
 func (s *BotTestSuite) TestSendContact() {
-	request := `{"chat_id":"130","phone_number":"+79998887766","first_name":"John","last_name":"Doe","reply_to_message_id":150}`
+	request := `{"chat_id":130,"phone_number":"+79998887766","first_name":"John","last_name":"Doe","reply_to_message_id":150}`
 	s.registerRequestCheck("sendContact", request)
 
 	message, err := s.bot.SendContact("130", "+79998887766", "John", "Doe", &SendContactOptions{
@@ -695,8 +866,38 @@ func (s *BotTestSuite) TestSendContact() {
 	s.Require().NotNil(message)
 }
 
+func (s *BotTestSuite) TestSendPoll() {
+	request := `{"chat_id":131,"question":"2+2?","options":[{"text":"3"},{"text":"4"}],"is_anonymous":false,"type":"quiz","correct_option_id":1,"explanation":"Math"}`
+	s.registerResultWithRequestCheck("sendPoll", `{"message_id":1,"poll":{"id":"p1","question":"2+2?","type":"quiz","correct_option_id":1}}`, request)
+
+	isAnonymous := false
+	correctOptionID := 1
+	message, err := s.bot.SendPoll("131", "2+2?", []InputPollOption{{Text: "3"}, {Text: "4"}}, &SendPollOptions{
+		IsAnonymous:     &isAnonymous,
+		Type:            POLL_TYPE_QUIZ,
+		CorrectOptionID: &correctOptionID,
+		Explanation:     "Math",
+	})
+
+	s.Require().Nil(err)
+	s.Require().NotNil(message.Poll)
+	s.Require().Equal("p1", message.Poll.ID)
+	s.Require().Equal(1, *message.Poll.CorrectOptionID)
+}
+
+func (s *BotTestSuite) TestStopPoll() {
+	request := `{"chat_id":131,"message_id":12}`
+	s.registerResultWithRequestCheck("stopPoll", `{"id":"p1","question":"q","is_closed":true,"total_voter_count":3}`, request)
+
+	poll, err := s.bot.StopPoll("131", 12, nil)
+
+	s.Require().Nil(err)
+	s.Require().True(poll.IsClosed)
+	s.Require().Equal(3, poll.TotalVoterCount)
+}
+
 func (s *BotTestSuite) TestForwardMessage() {
-	request := `{"chat_id":"131","disable_notification":true,"from_chat_id":"99","message_id":543}`
+	request := `{"chat_id":131,"disable_notification":true,"from_chat_id":99,"message_id":543}`
 	s.registerRequestCheck("forwardMessage", request)
 
 	message, err := s.bot.ForwardMessage("131", "99", 543, true)
@@ -705,8 +906,68 @@ func (s *BotTestSuite) TestForwardMessage() {
 	s.Require().NotNil(message)
 }
 
+func (s *BotTestSuite) TestCopyMessage() {
+	request := `{"chat_id":131,"from_chat_id":99,"message_id":543,"caption":"new caption","reply_markup":{"force_reply":true}}`
+	s.registerResultWithRequestCheck("copyMessage", `{"message_id":77}`, request)
+
+	messageID, err := s.bot.CopyMessage("131", "99", 543, &CopyMessageOptions{
+		Caption:     "new caption",
+		ReplyMarkup: ForceReply{ForceReply: true},
+	})
+
+	s.Require().Nil(err)
+	s.Require().Equal(int64(77), messageID.MessageID)
+}
+
+func (s *BotTestSuite) TestForwardMessages() {
+	request := `{"chat_id":131,"from_chat_id":99,"message_ids":[1,2,3],"protect_content":true}`
+	s.registerResultWithRequestCheck("forwardMessages", `[{"message_id":10},{"message_id":11},{"message_id":12}]`, request)
+
+	ids, err := s.bot.ForwardMessages("131", "99", []int64{1, 2, 3}, &ForwardMessagesOptions{
+		ProtectContent: true,
+	})
+
+	s.Require().Nil(err)
+	s.Require().Equal([]MessageID{{10}, {11}, {12}}, ids)
+}
+
+func (s *BotTestSuite) TestCopyMessages() {
+	ids := make([]int64, 150)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+
+	requests := [][]int64{}
+	httpmock.RegisterResponder("POST", s.bot.buildURL("copyMessages"), func(request *http.Request) (*http.Response, error) {
+		params := copyMessagesParams{}
+		if err := json.NewDecoder(request.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		s.Require().True(params.RemoveCaption)
+		requests = append(requests, params.MessageIDs)
+
+		result := []MessageID{}
+		for _, id := range params.MessageIDs {
+			result = append(result, MessageID{id + 1000})
+		}
+		data, _ := json.Marshal(result)
+
+		return httpmock.NewStringResponse(200, fmt.Sprintf(`{"ok":true, "result": %s}`, data)), nil
+	})
+
+	result, err := s.bot.CopyMessages("131", "99", ids, &CopyMessagesOptions{
+		RemoveCaption: true,
+	})
+
+	s.Require().Nil(err)
+	s.Require().Equal([][]int64{ids[:100], ids[100:]}, requests)
+	s.Require().Equal(150, len(result))
+	s.Require().Equal(int64(1001), result[0].MessageID)
+	s.Require().Equal(int64(1150), result[149].MessageID)
+}
+
 func (s *BotTestSuite) TestSendChatAction() {
-	request := `{"action":"typing","chat_id":"132"}`
+	request := `{"action":"typing","chat_id":132}`
 	s.registerRequestCheck("sendChatAction", request)
 
 	err := s.bot.SendChatAction("132", CHAT_ACTION_TYPING)
@@ -725,7 +986,7 @@ func (s *BotTestSuite) TestAnswerCallbackQuery() {
 }
 
 func (s *BotTestSuite) TestKickChatMember() {
-	request := `{"chat_id":"1","user_id":2}`
+	request := `{"chat_id":1,"user_id":2}`
 	s.registerRequestCheck("kickChatMember", request)
 
 	err := s.bot.KickChatMember("1", 2)
@@ -733,7 +994,7 @@ func (s *BotTestSuite) TestKickChatMember() {
 }
 
 func (s *BotTestSuite) TestLeaveChat() {
-	request := `{"chat_id":"143"}`
+	request := `{"chat_id":143}`
 	s.registerRequestCheck("leaveChat", request)
 
 	err := s.bot.LeaveChat("143")
@@ -741,7 +1002,7 @@ func (s *BotTestSuite) TestLeaveChat() {
 }
 
 func (s *BotTestSuite) TestUnbanChatMember() {
-	request := `{"chat_id":"22","user_id":33}`
+	request := `{"chat_id":22,"user_id":33}`
 	s.registerRequestCheck("unbanChatMember", request)
 
 	err := s.bot.UnbanChatMember("22", 33)
@@ -780,7 +1041,7 @@ func (s *BotTestSuite) TestGetUserProfilePhotos() {
 }
 
 func (s *BotTestSuite) TestSendMessage() {
-	request := `{"reply_to_message_id":89,"parse_mode":"HTML","chat_id":"3434","text":"mss"}`
+	request := `{"reply_to_message_id":89,"parse_mode":"HTML","chat_id":3434,"text":"mss"}`
 	s.registerRequestCheck("sendMessage", request)
 
 	_, err := s.bot.SendMessage("3434", "mss", &SendMessageOptions{
@@ -790,8 +1051,122 @@ func (s *BotTestSuite) TestSendMessage() {
 	s.Require().Nil(err)
 }
 
+func (s *BotTestSuite) TestSendMessageWithReplyParameters() {
+	request := `{
+		"chat_id": 3434,
+		"text": "bold",
+		"entities": [{"type": "bold", "offset": 0, "length": 4}],
+		"link_preview_options": {"is_disabled": true},
+		"protect_content": true,
+		"message_effect_id": "5104841245755180586",
+		"reply_parameters": {
+			"message_id": 89,
+			"chat_id": "@channel",
+			"allow_sending_without_reply": true,
+			"quote": "quoted"
+		}
+	}`
+	s.registerRequestCheck("sendMessage", request)
+
+	_, err := s.bot.SendMessage("3434", "bold", &SendMessageOptions{
+		Entities:           []MessageEntity{{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 4}},
+		LinkPreviewOptions: &LinkPreviewOptions{IsDisabled: true},
+		ProtectContent:     true,
+		MessageEffectID:    "5104841245755180586",
+		ReplyParameters: &ReplyParameters{
+			MessageID:                89,
+			ChatID:                   "@channel",
+			AllowSendingWithoutReply: true,
+			Quote:                    "quoted",
+		},
+	})
+	s.Require().Nil(err)
+}
+
+func (s *BotTestSuite) TestSendLongMessage() {
+	requests := []map[string]json.RawMessage{}
+	httpmock.RegisterResponder("POST", s.bot.buildURL("sendMessage"), func(request *http.Request) (*http.Response, error) {
+		params := map[string]json.RawMessage{}
+		if err := json.NewDecoder(request.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		requests = append(requests, params)
+
+		return httpmock.NewStringResponse(200, fmt.Sprintf(`{"ok":true, "result": {"message_id": %d}}`, len(requests))), nil
+	})
+
+	paragraph := strings.Repeat("a", 3000)
+	text := "<b>" + paragraph + "</b>\n\n" + paragraph + "\n\n<i>end</i>"
+	messages, err := s.bot.SendLongMessage("3434", text, &SendMessageOptions{
+		ParseMode:       PARSE_MODE_HTML,
+		ReplyParameters: &ReplyParameters{MessageID: 1},
+		ReplyMarkup:     ForceReply{ForceReply: true},
+	})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(messages))
+	s.Require().Equal(int64(2), messages[1].MessageID)
+
+	s.Require().Equal(2, len(requests))
+	s.JSONEq(fmt.Sprintf(`{"chat_id":3434,"text":"%s","entities":[{"type":"bold","offset":0,"length":3000}],"reply_parameters":{"message_id":1}}`, paragraph), string(mustMarshal(requests[0])))
+	s.JSONEq(fmt.Sprintf(`{"chat_id":3434,"text":"%s\n\nend","entities":[{"type":"italic","offset":3002,"length":3}],"reply_markup":{"force_reply":true}}`, paragraph), string(mustMarshal(requests[1])))
+
+	// MarkdownV2 is split the same way
+	requests = requests[:0]
+	markdown := "*" + paragraph + "*\n\n" + paragraph + "\n\n_end_"
+	messages, err = s.bot.SendLongMessage("3434", markdown, &SendMessageOptions{ParseMode: PARSE_MODE_MARKDOWN_V2})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(messages))
+	s.JSONEq(fmt.Sprintf(`{"chat_id":3434,"text":"%s","entities":[{"type":"bold","offset":0,"length":3000}]}`, paragraph), string(mustMarshal(requests[0])))
+	s.JSONEq(fmt.Sprintf(`{"chat_id":3434,"text":"%s\n\nend","entities":[{"type":"italic","offset":3002,"length":3}]}`, paragraph), string(mustMarshal(requests[1])))
+
+	_, err = s.bot.SendLongMessage("3434", text, &SendMessageOptions{ParseMode: PARSE_MODE_MARKDOWN})
+	s.Require().Equal(ErrSplitParseMode, err)
+
+	_, err = s.bot.SendLongMessage("3434", "<b></b>", &SendMessageOptions{ParseMode: PARSE_MODE_HTML})
+	s.Require().Equal(ErrEmptyText, err)
+	s.Require().Equal(2, len(requests))
+}
+
+func (s *BotTestSuite) TestSendPhotoWithLongCaption() {
+	requests := []map[string]json.RawMessage{}
+	record := func(request *http.Request) (*http.Response, error) {
+		params := map[string]json.RawMessage{}
+		if err := json.NewDecoder(request.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+		requests = append(requests, params)
+
+		return httpmock.NewStringResponse(200, fmt.Sprintf(`{"ok":true, "result": {"message_id": %d}}`, len(requests))), nil
+	}
+	httpmock.RegisterResponder("POST", s.bot.buildURL("sendPhoto"), record)
+	httpmock.RegisterResponder("POST", s.bot.buildURL("sendMessage"), record)
+
+	paragraph := strings.Repeat("a", 1000)
+	messages, err := s.bot.SendPhotoWithLongCaption("3434", "ph", &SendPhotoOptions{
+		Caption:             "<b>" + paragraph + "</b>\n\n<i>" + paragraph + "</i>",
+		ParseMode:           PARSE_MODE_HTML,
+		DisableNotification: true,
+		ReplyParameters:     &ReplyParameters{MessageID: 1},
+		ReplyMarkup:         ForceReply{ForceReply: true},
+	})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(messages))
+	s.Require().Equal(int64(2), messages[1].MessageID)
+
+	s.Require().Equal(2, len(requests))
+	s.JSONEq(fmt.Sprintf(`{"chat_id":3434,"photo":"ph","caption":"%s","caption_entities":[{"type":"bold","offset":0,"length":1000}],"disable_notification":true,"reply_parameters":{"message_id":1}}`, paragraph), string(mustMarshal(requests[0])))
+	s.JSONEq(fmt.Sprintf(`{"chat_id":3434,"text":"%s","entities":[{"type":"italic","offset":0,"length":1000}],"disable_notification":true,"reply_markup":{"force_reply":true}}`, paragraph), string(mustMarshal(requests[1])))
+
+	// Short caption is sent as is
+	requests = requests[:0]
+	messages, err = s.bot.SendPhotoWithLongCaption("3434", "ph", &SendPhotoOptions{Caption: "short", ReplyMarkup: ForceReply{ForceReply: true}})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(messages))
+	s.JSONEq(`{"chat_id":3434,"photo":"ph","caption":"short","reply_markup":{"force_reply":true}}`, string(mustMarshal(requests[0])))
+}
+
 func (s *BotTestSuite) TestSendGame() {
-	request := `{"chat_id":"298","game_short_name":"ggg","reply_to_message_id":892}`
+	request := `{"chat_id":298,"game_short_name":"ggg","reply_to_message_id":892}`
 	s.registerRequestCheck("sendGame", request)
 
 	_, err := s.bot.SendGame("298", "ggg", &SendGameOptions{
@@ -801,7 +1176,7 @@ func (s *BotTestSuite) TestSendGame() {
 }
 
 func (s *BotTestSuite) TestSetGameScore() {
-	request := `{"user_id":1,"score":777,"chat_id":"552","message_id":892,"inline_message_id":"stf","disable_edit_message":true}`
+	request := `{"user_id":1,"score":777,"chat_id":552,"message_id":892,"inline_message_id":"stf","disable_edit_message":true}`
 	s.registerRequestCheck("setGameScore", request)
 
 	_, err := s.bot.SetGameScore(1, 777, &SetGameScoreOptions{
@@ -871,7 +1246,7 @@ func (s *BotTestSuite) TestGetGameHighScorese() {
 }
 
 func (s *BotTestSuite) TestEditMessageText() {
-	request := `{"chat_id":"143","message_id":67,"inline_message_id":"gyt","text":"new text","parse_mode":"Markdown"}`
+	request := `{"chat_id":143,"message_id":67,"inline_message_id":"gyt","text":"new text","parse_mode":"Markdown"}`
 	s.registerRequestCheck("editMessageText", request)
 
 	_, err := s.bot.EditMessageText("143", 67, "gyt", "new text", &EditMessageTextOptions{
@@ -882,7 +1257,7 @@ func (s *BotTestSuite) TestEditMessageText() {
 }
 
 func (s *BotTestSuite) TestEditMessageCaption() {
-	request := `{"chat_id":"490","message_id":87,"inline_message_id":"ubl","caption":"ca"}`
+	request := `{"chat_id":490,"message_id":87,"inline_message_id":"ubl","caption":"ca"}`
 	s.registerRequestCheck("editMessageCaption", request)
 
 	_, err := s.bot.EditMessageCaption("490", 87, "ubl", &EditMessageCationOptions{
@@ -892,8 +1267,22 @@ func (s *BotTestSuite) TestEditMessageCaption() {
 	s.Require().Nil(err)
 }
 
+func (s *BotTestSuite) TestEditMessageCaptionWithEntities() {
+	request := `{"business_connection_id":"bc","chat_id":490,"message_id":87,"caption":"ca","caption_entities":[{"type":"italic","offset":0,"length":2}],"show_caption_above_media":true}`
+	s.registerRequestCheck("editMessageCaption", request)
+
+	_, err := s.bot.EditMessageCaption("490", 87, "", &EditMessageCationOptions{
+		BusinessConnectionID:  "bc",
+		Caption:               "ca",
+		CaptionEntities:       []MessageEntity{{Type: MESSAGE_ENTITY_ITALIC, Offset: 0, Length: 2}},
+		ShowCaptionAboveMedia: true,
+	})
+
+	s.Require().Nil(err)
+}
+
 func (s *BotTestSuite) TestEditMessageReplyMarkup() {
-	request := `{"chat_id":"781","message_id":32,"inline_message_id":"zzt","reply_markup":{"force_reply":true,"selective":true}}`
+	request := `{"chat_id":781,"message_id":32,"inline_message_id":"zzt","reply_markup":{"force_reply":true,"selective":true}}`
 	s.registerRequestCheck("editMessageReplyMarkup", request)
 
 	_, err := s.bot.EditMessageReplyMarkup("781", 32, "zzt", ForceReply{
@@ -904,9 +1293,41 @@ func (s *BotTestSuite) TestEditMessageReplyMarkup() {
 	s.Require().Nil(err)
 }
 
+func (s *BotTestSuite) TestEditMessageMedia() {
+	request := `{"chat_id":781,"message_id":32,"media":{"type":"photo","media":"ph2","caption":"new"}}`
+	s.registerRequestCheck("editMessageMedia", request)
+
+	_, err := s.bot.EditMessageMedia("781", 32, "", InputMediaPhoto{
+		Type:    INPUT_MEDIA_TYPE_PHOTO,
+		Media:   "ph2",
+		Caption: "new",
+	}, nil)
+
+	s.Require().Nil(err)
+}
+
+func (s *BotTestSuite) TestEditMessageMediaFiles() {
+	file := NewInputFile("new", strings.NewReader("photo data"), "photo.png")
+	values := url.Values{
+		"chat_id":    {"781"},
+		"message_id": {"32"},
+		"media":      {`{"type":"photo","media":"attach://new","caption":"new"}`},
+	}
+	s.registeMultipartrRequestCheck("editMessageMedia", values, fileField{
+		Source:    strings.NewReader("photo data"),
+		Fieldname: "new",
+		Filename:  "photo.png",
+	})
+
+	media := NewInputMediaPhoto(file.Attach())
+	media.Caption = "new"
+	_, err := s.bot.EditMessageMediaFiles("781", 32, media, []InputFile{file}, nil)
+	s.Require().Nil(err)
+}
+
 func (s *BotTestSuite) TestDeleteMessage() {
 	s.registerResultWithRequestCheck("deleteMessage", "true", `{
-		"chat_id": "111",
+		"chat_id": 111,
 		"message_id": 124
 	}`)
 
@@ -916,7 +1337,7 @@ func (s *BotTestSuite) TestDeleteMessage() {
 
 	httpmock.Reset()
 	s.registerResultWithRequestCheck("deleteMessage", "false", `{
-		"chat_id": "222",
+		"chat_id": 222,
 		"message_id": 431
 	}`)
 
@@ -925,6 +1346,33 @@ func (s *BotTestSuite) TestDeleteMessage() {
 	s.Require().False(success)
 }
 
+func (s *BotTestSuite) TestDeleteMessages() {
+	s.registerResultWithRequestCheck("deleteMessages", "true", `{
+		"chat_id": 111,
+		"message_ids": [124, 125]
+	}`)
+
+	err := s.bot.DeleteMessages("111", []int64{125, 124, 125})
+	s.Require().Nil(err)
+}
+
+func (s *BotTestSuite) TestSetMessageReaction() {
+	request := `{"chat_id":111,"message_id":124,"reaction":[{"type":"emoji","emoji":"👍"},{"type":"custom_emoji","custom_emoji_id":"5368324170671202286"}],"is_big":true}`
+	s.registerResultWithRequestCheck("setMessageReaction", "true", request)
+
+	err := s.bot.SetMessageReaction("111", 124, []ReactionType{
+		NewEmojiReaction("👍"),
+		NewCustomEmojiReaction("5368324170671202286"),
+	}, true)
+	s.Require().Nil(err)
+
+	httpmock.Reset()
+	s.registerResultWithRequestCheck("setMessageReaction", "true", `{"chat_id":111,"message_id":124,"reaction":[]}`)
+
+	err = s.bot.SetMessageReaction("111", 124, nil, false)
+	s.Require().Nil(err)
+}
+
 func (s *BotTestSuite) TestAnswerInlineQuery() {
 	request := `{"inline_query_id":"aaa","results":[{"type":"article","id":"124","title":"Article"}],"cache_time":42,"is_personal":true,"next_offset":"2","switch_pm_text":"yes","switch_pm_parameter":"no"}`
 	s.registerRequestCheck("answerInlineQuery", request)
@@ -945,6 +1393,56 @@ func (s *BotTestSuite) TestAnswerInlineQuery() {
 	s.Require().Nil(err)
 }
 
+func (s *BotTestSuite) TestInlinePaginatorAnswer() {
+	request := `{"inline_query_id":"aaa","results":[{"type":"article","id":"3","title":"Item 3","input_message_content":{"message_text":"3"}}],"next_offset":"3","cache_time":10,"button":{"text":"Open","web_app":{"url":"https://example.com"}}}`
+	s.registerRequestCheck("answerInlineQuery", request)
+
+	paginator := NewInlinePaginator(1, func(query InlineQuery, offset, limit int) ([]int, error) {
+		items := []int{1, 2, 3, 4}
+		return items[offset:min(offset+limit, len(items))], nil
+	}, func(item int) InlineQueryResult {
+		id := strconv.Itoa(item)
+		return NewInlineQueryResultArticle(id, "Item "+id, InputTextMessageContent{MessageText: id})
+	})
+
+	err := paginator.Answer(s.bot, InlineQuery{ID: "aaa", Offset: "2"}, &AnswerInlineQueryOptions{
+		CacheTime:  10,
+		NextOffset: "ignored",
+		Button:     NewInlineQueryResultsButtonWebApp("Open", "https://example.com"),
+	})
+	s.Require().Nil(err)
+}
+
+func (s *BotTestSuite) TestAnswerWebAppQuery() {
+	request := `{"web_app_query_id":"q1","result":{"type":"article","id":"1","title":"Title","input_message_content":{"message_text":"text"}}}`
+	s.registerResultWithRequestCheck("answerWebAppQuery", `{"inline_message_id":"m1"}`, request)
+
+	message, err := s.bot.AnswerWebAppQuery("q1", NewInlineQueryResultArticle("1", "Title", InputTextMessageContent{MessageText: "text"}))
+	s.Require().Nil(err)
+	s.Require().Equal("m1", message.InlineMessageID)
+}
+
+func (s *BotTestSuite) TestSavePreparedInlineMessage() {
+	request := `{"user_id":42,"result":{"type":"game","id":"1","game_short_name":"game"},"allow_user_chats":true,"allow_group_chats":true}`
+	s.registerResultWithRequestCheck("savePreparedInlineMessage", `{"id":"p1","expiration_date":1700000000}`, request)
+
+	message, err := s.bot.SavePreparedInlineMessage(42, NewInlineQueryResultGame("1", "game"), &SavePreparedInlineMessageOptions{
+		AllowUserChats:  true,
+		AllowGroupChats: true,
+	})
+	s.Require().Nil(err)
+	s.Require().Equal(&PreparedInlineMessage{ID: "p1", ExpirationDate: 1700000000}, message)
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
+
 func TestBotTestSuite(t *testing.T) {
 	suite.Run(t, new(BotTestSuite))
 }
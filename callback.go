@@ -0,0 +1,520 @@
+package micha
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	callbackFlagStored byte = 1 << iota
+
+	callbackNameSeparator = ":"
+	callbackKeySize       = 9
+	defaultSignatureSize  = 8
+)
+
+var (
+	ErrInvalidCallbackData      = errors.New("invalid callback data")
+	ErrInvalidCallbackSignature = errors.New("invalid callback data signature")
+	ErrCallbackDataNotFound     = errors.New("callback data not found")
+	ErrUnsupportedCallbackType  = errors.New("unsupported callback data type")
+)
+
+var callbackEncoding = base64.RawURLEncoding
+
+// CallbackStore keeps payloads which don't fit into callback data.
+// Keys are derived from payloads, so setting the same payload again doesn't add entries.
+type CallbackStore interface {
+	Set(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+}
+
+// DefaultCallbackStoreSize - default number of payloads kept by MemoryCallbackStore
+const DefaultCallbackStoreSize = 10000
+
+// MemoryCallbackStore - in-memory CallbackStore with limited size,
+// least recently used payloads are evicted when the store is full
+type MemoryCallbackStore struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	data  map[string]*list.Element
+}
+
+type memoryCallbackEntry struct {
+	key  string
+	data []byte
+}
+
+// NewMemoryCallbackStore - create new in-memory callback store keeping up to size payloads
+// (DefaultCallbackStoreSize if size is 0)
+func NewMemoryCallbackStore(size int) *MemoryCallbackStore {
+	if size <= 0 {
+		size = DefaultCallbackStoreSize
+	}
+
+	return &MemoryCallbackStore{
+		size:  size,
+		order: list.New(),
+		data:  map[string]*list.Element{},
+	}
+}
+
+func (s *MemoryCallbackStore) Set(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.data[key]; ok {
+		element.Value.(*memoryCallbackEntry).data = append([]byte(nil), data...)
+		s.order.MoveToFront(element)
+		return nil
+	}
+
+	s.data[key] = s.order.PushFront(&memoryCallbackEntry{key: key, data: append([]byte(nil), data...)})
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.data, oldest.Value.(*memoryCallbackEntry).key)
+	}
+
+	return nil
+}
+
+func (s *MemoryCallbackStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.data[key]
+	if !ok {
+		return nil, ErrCallbackDataNotFound
+	}
+	s.order.MoveToFront(element)
+
+	return append([]byte(nil), element.Value.(*memoryCallbackEntry).data...), nil
+}
+
+func (s *MemoryCallbackStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.data[key]; ok {
+		s.order.Remove(element)
+		delete(s.data, key)
+	}
+
+	return nil
+}
+
+// Len - return number of stored payloads
+func (s *MemoryCallbackStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+// CallbackCodec packs structs into callback data.
+//
+// Data has format "<name>:<base64 payload>" (or just payload for empty name).
+// Exported struct fields are encoded in declaration order with compact binary encoding,
+// fields tagged with `callback:"-"` are skipped.
+// Payload is signed with HMAC-SHA256 when secret is set,
+// payloads longer than MaxCallbackDataLength are moved into the store if one is set.
+type CallbackCodec struct {
+	secret        []byte
+	signatureSize int
+	store         CallbackStore
+}
+
+type CallbackCodecOption func(*CallbackCodec)
+
+// WithCallbackSecret - sign callback data with HMAC-SHA256 truncated to size bytes (8 if size is 0)
+func WithCallbackSecret(secret []byte, size int) CallbackCodecOption {
+	return func(c *CallbackCodec) {
+		if size <= 0 || size > sha256.Size {
+			size = defaultSignatureSize
+		}
+		c.secret = secret
+		c.signatureSize = size
+	}
+}
+
+// WithCallbackStore - store payloads longer than MaxCallbackDataLength in store
+func WithCallbackStore(store CallbackStore) CallbackCodecOption {
+	return func(c *CallbackCodec) {
+		c.store = store
+	}
+}
+
+// NewCallbackCodec - create new callback data codec
+func NewCallbackCodec(opts ...CallbackCodecOption) *CallbackCodec {
+	codec := &CallbackCodec{}
+	for _, opt := range opts {
+		opt(codec)
+	}
+
+	return codec
+}
+
+// Encode - pack value into callback data, name can be used for routing (see Name)
+func (c *CallbackCodec) Encode(name string, value interface{}) (string, error) {
+	if strings.Contains(name, callbackNameSeparator) {
+		return "", fmt.Errorf("callback name %q contains %q", name, callbackNameSeparator)
+	}
+
+	payload, err := encodeCallbackValue(nil, reflect.Indirect(reflect.ValueOf(value)))
+	if err != nil {
+		return "", err
+	}
+
+	data := c.format(name, 0, payload)
+	if len(data) <= MaxCallbackDataLength {
+		return data, nil
+	}
+
+	if c.store == nil {
+		return "", ErrCallbackDataTooLong
+	}
+
+	key := callbackStoreKey(payload)
+	if err := c.store.Set(callbackEncoding.EncodeToString(key), payload); err != nil {
+		return "", err
+	}
+
+	data = c.format(name, callbackFlagStored, key)
+	if len(data) > MaxCallbackDataLength {
+		return "", ErrCallbackDataTooLong
+	}
+
+	return data, nil
+}
+
+// Button - create callback button with encoded value
+func (c *CallbackCodec) Button(text, name string, value interface{}) (InlineKeyboardButton, error) {
+	data, err := c.Encode(name, value)
+	if err != nil {
+		return InlineKeyboardButton{}, err
+	}
+
+	return NewInlineButtonCallback(text, data), nil
+}
+
+// Name - return name of encoded callback data
+func (c *CallbackCodec) Name(data string) string {
+	name, _, found := strings.Cut(data, callbackNameSeparator)
+	if !found {
+		return ""
+	}
+
+	return name
+}
+
+// Decode - verify and unpack callback data into target, target must be a pointer
+func (c *CallbackCodec) Decode(data string, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("%w: target must be a non-nil pointer", ErrUnsupportedCallbackType)
+	}
+
+	flags, payload, err := c.verify(data)
+	if err != nil {
+		return err
+	}
+	if flags&callbackFlagStored != 0 {
+		payload, err = c.load(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	d := callbackDecoder{data: payload}
+	if err := d.decode(v.Elem()); err != nil {
+		return err
+	}
+	if len(d.data) != 0 {
+		return ErrInvalidCallbackData
+	}
+
+	return nil
+}
+
+// Decode - unpack callback query data into target
+func (q CallbackQuery) Decode(codec *CallbackCodec, target interface{}) error {
+	return codec.Decode(q.Data, target)
+}
+
+// Delete - remove payload of callback data from the store, e.g. when its keyboard is removed.
+// Signature is verified like in Decode. Callback data which is not stored is ignored.
+// Payloads are stored by content, so every button encoding the same value loses its payload,
+// don't delete payloads which may be still used by other keyboards.
+func (c *CallbackCodec) Delete(data string) error {
+	flags, key, err := c.verify(data)
+	if err != nil {
+		return err
+	}
+	if c.store == nil || flags&callbackFlagStored == 0 {
+		return nil
+	}
+
+	return c.store.Delete(callbackEncoding.EncodeToString(key))
+}
+
+// verify - check signature of callback data, returns flags and payload or store key
+func (c *CallbackCodec) verify(data string) (byte, []byte, error) {
+	name, encoded, found := strings.Cut(data, callbackNameSeparator)
+	if !found {
+		name, encoded = "", data
+	}
+
+	blob, err := callbackEncoding.DecodeString(encoded)
+	if err != nil || len(blob) < 1+c.signatureSize {
+		return 0, nil, ErrInvalidCallbackData
+	}
+
+	if c.secret != nil {
+		body, signature := blob[:len(blob)-c.signatureSize], blob[len(blob)-c.signatureSize:]
+		if !hmac.Equal(signature, c.sign(name, body)) {
+			return 0, nil, ErrInvalidCallbackSignature
+		}
+		blob = body
+	}
+
+	return blob[0], blob[1:], nil
+}
+
+// callbackStoreKey - key of stored payload, the same payload always gets the same key
+func callbackStoreKey(payload []byte) []byte {
+	sum := sha256.Sum256(payload)
+	return sum[:callbackKeySize]
+}
+
+func (c *CallbackCodec) load(key []byte) ([]byte, error) {
+	if c.store == nil {
+		return nil, ErrCallbackDataNotFound
+	}
+
+	return c.store.Get(callbackEncoding.EncodeToString(key))
+}
+
+func (c *CallbackCodec) format(name string, flags byte, payload []byte) string {
+	blob := append([]byte{flags}, payload...)
+	if c.secret != nil {
+		blob = append(blob, c.sign(name, blob)...)
+	}
+
+	encoded := callbackEncoding.EncodeToString(blob)
+	if name == "" {
+		return encoded
+	}
+
+	return name + callbackNameSeparator + encoded
+}
+
+func (c *CallbackCodec) sign(name string, blob []byte) []byte {
+	hm := hmac.New(sha256.New, c.secret)
+	hm.Write([]byte(name))
+	hm.Write([]byte(callbackNameSeparator))
+	hm.Write(blob)
+
+	return hm.Sum(nil)[:c.signatureSize]
+}
+
+func callbackFieldSkipped(field reflect.StructField) bool {
+	return !field.IsExported() || field.Tag.Get("callback") == "-"
+}
+
+func encodeCallbackValue(buf []byte, v reflect.Value) ([]byte, error) {
+	var err error
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(buf, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(buf, v.Uint()), nil
+	case reflect.Float32:
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		buf = binary.AppendUvarint(buf, uint64(v.Len()))
+		return append(buf, v.String()...), nil
+	case reflect.Slice:
+		buf = binary.AppendUvarint(buf, uint64(v.Len()))
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return append(buf, v.Bytes()...), nil
+		}
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if buf, err = encodeCallbackValue(buf, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return append(buf, 0), nil
+		}
+		return encodeCallbackValue(append(buf, 1), v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if callbackFieldSkipped(v.Type().Field(i)) {
+				continue
+			}
+			if buf, err = encodeCallbackValue(buf, v.Field(i)); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedCallbackType, v.Kind())
+}
+
+type callbackDecoder struct {
+	data []byte
+}
+
+func (d *callbackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data) {
+		return nil, ErrInvalidCallbackData
+	}
+
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+func (d *callbackDecoder) uvarint() (uint64, error) {
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, ErrInvalidCallbackData
+	}
+	d.data = d.data[n:]
+
+	return x, nil
+}
+
+func (d *callbackDecoder) length() (int, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	// Every encoded element takes at least one byte
+	if n > uint64(len(d.data)) {
+		return 0, ErrInvalidCallbackData
+	}
+
+	return int(n), nil
+}
+
+func (d *callbackDecoder) decode(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := d.next(1)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b[0] != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, n := binary.Varint(d.data)
+		if n <= 0 || v.OverflowInt(x) {
+			return ErrInvalidCallbackData
+		}
+		d.data = d.data[n:]
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, err := d.uvarint()
+		if err != nil || v.OverflowUint(x) {
+			return ErrInvalidCallbackData
+		}
+		v.SetUint(x)
+	case reflect.Float32:
+		b, err := d.next(4)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+	case reflect.Float64:
+		b, err := d.next(8)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	case reflect.String:
+		n, err := d.length()
+		if err != nil {
+			return err
+		}
+		b, _ := d.next(n)
+		v.SetString(string(b))
+	case reflect.Slice:
+		n, err := d.length()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			// Empty slices are decoded as nil
+			v.SetZero()
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, _ := d.next(n)
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		return d.elements(v)
+	case reflect.Array:
+		return d.elements(v)
+	case reflect.Pointer:
+		b, err := d.next(1)
+		if err != nil {
+			return err
+		}
+		if b[0] == 0 {
+			v.SetZero()
+			return nil
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		return d.decode(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if callbackFieldSkipped(v.Type().Field(i)) {
+				continue
+			}
+			if err := d.decode(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedCallbackType, v.Kind())
+	}
+
+	return nil
+}
+
+func (d *callbackDecoder) elements(v reflect.Value) error {
+	for i := 0; i < v.Len(); i++ {
+		if err := d.decode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
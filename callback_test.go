@@ -0,0 +1,150 @@
+package micha
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCallbackPage struct {
+	Section string
+	Page    uint16
+	Offset  int64
+	Active  bool
+	Score   float64
+	Tags    []string
+	Parent  *testCallbackPage
+	Cache   string `callback:"-"`
+	hidden  int
+}
+
+func TestCallbackCodec(t *testing.T) {
+	codec := NewCallbackCodec()
+
+	value := testCallbackPage{
+		Section: "news",
+		Page:    300,
+		Offset:  -5,
+		Active:  true,
+		Score:   0.5,
+		Tags:    []string{"a", "b"},
+		Parent:  &testCallbackPage{Page: 1},
+		Cache:   "skipped",
+		hidden:  1,
+	}
+	data, err := codec.Encode("page", value)
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(data, "page:"))
+	require.LessOrEqual(t, len(data), MaxCallbackDataLength)
+	require.Equal(t, "page", codec.Name(data))
+
+	decoded := testCallbackPage{}
+	require.Nil(t, CallbackQuery{Data: data}.Decode(codec, &decoded))
+	value.Cache = ""
+	value.hidden = 0
+	require.Equal(t, value, decoded)
+
+	// Pointer values are encoded the same way as values
+	pointerData, err := codec.Encode("page", &value)
+	require.Nil(t, err)
+	require.Equal(t, data, pointerData)
+
+	data, err = codec.Encode("", uint8(7))
+	require.Nil(t, err)
+	require.Equal(t, "", codec.Name(data))
+	n := uint8(0)
+	require.Nil(t, codec.Decode(data, &n))
+	require.Equal(t, uint8(7), n)
+
+	require.ErrorIs(t, codec.Decode("page:!!!", &decoded), ErrInvalidCallbackData)
+	require.ErrorIs(t, codec.Decode(data, &decoded), ErrInvalidCallbackData)
+	require.ErrorIs(t, codec.Decode(data, decoded), ErrUnsupportedCallbackType)
+
+	_, err = codec.Encode("bad:name", 1)
+	require.NotNil(t, err)
+	_, err = codec.Encode("map", map[string]int{})
+	require.ErrorIs(t, err, ErrUnsupportedCallbackType)
+}
+
+func TestCallbackCodecSigning(t *testing.T) {
+	codec := NewCallbackCodec(WithCallbackSecret([]byte("secret"), 0))
+
+	data, err := codec.Encode("vote", 42)
+	require.Nil(t, err)
+
+	n := 0
+	require.Nil(t, codec.Decode(data, &n))
+	require.Equal(t, 42, n)
+
+	// Payload can't be moved to other name or decoded with other secret
+	require.ErrorIs(t, codec.Decode("like"+data[len("vote"):], &n), ErrInvalidCallbackSignature)
+	other := NewCallbackCodec(WithCallbackSecret([]byte("other"), 0))
+	require.ErrorIs(t, other.Decode(data, &n), ErrInvalidCallbackSignature)
+
+	forged, err := NewCallbackCodec().Encode("vote", 43)
+	require.Nil(t, err)
+	require.ErrorIs(t, codec.Decode(forged+"AAAAAAAAAAA", &n), ErrInvalidCallbackSignature)
+}
+
+func TestCallbackCodecStore(t *testing.T) {
+	value := testCallbackPage{Section: strings.Repeat("x", 100)}
+
+	_, err := NewCallbackCodec().Encode("page", value)
+	require.ErrorIs(t, err, ErrCallbackDataTooLong)
+
+	store := NewMemoryCallbackStore(2)
+	codec := NewCallbackCodec(WithCallbackSecret([]byte("secret"), 4), WithCallbackStore(store))
+
+	button, err := codec.Button("Next", "page", value)
+	require.Nil(t, err)
+	require.Nil(t, button.Validate())
+	require.Equal(t, 1, store.Len())
+
+	// The same payload is stored once
+	again, err := codec.Button("Next", "page", value)
+	require.Nil(t, err)
+	require.Equal(t, button.CallbackData, again.CallbackData)
+	require.Equal(t, 1, store.Len())
+
+	decoded := testCallbackPage{}
+	require.Nil(t, codec.Decode(button.CallbackData, &decoded))
+	require.Equal(t, value, decoded)
+
+	// Forged data with the same store key can't delete the payload
+	forger := NewCallbackCodec(WithCallbackSecret([]byte("other"), 4), WithCallbackStore(NewMemoryCallbackStore(0)))
+	forged, err := forger.Encode("page", value)
+	require.Nil(t, err)
+	require.ErrorIs(t, codec.Delete(forged), ErrInvalidCallbackSignature)
+	require.Equal(t, 1, store.Len())
+
+	require.Nil(t, codec.Delete(button.CallbackData))
+	require.Equal(t, 0, store.Len())
+	require.ErrorIs(t, codec.Decode(button.CallbackData, &decoded), ErrCallbackDataNotFound)
+
+	// Not stored data is ignored
+	short, err := codec.Encode("page", testCallbackPage{Section: "x"})
+	require.Nil(t, err)
+	require.Nil(t, codec.Delete(short))
+	require.ErrorIs(t, codec.Delete("page:!"), ErrInvalidCallbackData)
+}
+
+func TestMemoryCallbackStoreEviction(t *testing.T) {
+	store := NewMemoryCallbackStore(2)
+	require.Nil(t, store.Set("a", []byte("1")))
+	require.Nil(t, store.Set("b", []byte("2")))
+
+	// Get makes "a" recently used, so "b" is evicted
+	_, err := store.Get("a")
+	require.Nil(t, err)
+	require.Nil(t, store.Set("c", []byte("3")))
+	require.Equal(t, 2, store.Len())
+
+	_, err = store.Get("b")
+	require.ErrorIs(t, err, ErrCallbackDataNotFound)
+	data, err := store.Get("a")
+	require.Nil(t, err)
+	require.Equal(t, []byte("1"), data)
+
+	require.Equal(t, DefaultCallbackStoreSize, NewMemoryCallbackStore(0).size)
+}
@@ -0,0 +1,376 @@
+package micha
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// REDACTED_TOKEN replaces bot token in recorded cassettes
+	REDACTED_TOKEN = "<BOT_TOKEN>"
+)
+
+type CassetteMatch int
+
+const (
+	// Requests must come in recorded order with the same method and params
+	CASSETTE_MATCH_STRICT CassetteMatch = iota
+	// Requests are matched with the first unused entry of the same method,
+	// entry with the same params is preferred
+	CASSETTE_MATCH_LENIENT
+)
+
+var (
+	ErrCassetteMismatch  = errors.New("request doesn't match cassette")
+	ErrCassetteExhausted = errors.New("cassette is exhausted")
+)
+
+// CassetteEntry - recorded request and response pair
+type CassetteEntry struct {
+	Method     string            `json:"method"` // API method or "file/<file path>" for downloads
+	Params     map[string]string `json:"params,omitempty"`
+	StatusCode int               `json:"status_code"`
+
+	// Optional
+	Response json.RawMessage `json:"response,omitempty"` // JSON response body
+	Data     []byte          `json:"data,omitempty"`     // Non JSON response body (downloaded files)
+}
+
+// CassetteRecorder - HttpClient which passes requests to the wrapped client
+// and writes every request and response pair to JSONL cassette.
+// The bot token is redacted from recorded entries.
+type CassetteRecorder struct {
+	mu     sync.Mutex
+	client HttpClient
+	writer io.Writer
+}
+
+// NewCassetteRecorder - create recorder, http.DefaultClient is used if client is nil
+func NewCassetteRecorder(client HttpClient, w io.Writer) *CassetteRecorder {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &CassetteRecorder{
+		client: client,
+		writer: w,
+	}
+}
+
+// Do - send request and record it
+func (r *CassetteRecorder) Do(request *http.Request) (*http.Response, error) {
+	method, token := cassetteMethod(request)
+	params, err := cassetteParams(request, token)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := CassetteEntry{
+		Method:     method,
+		Params:     params,
+		StatusCode: response.StatusCode,
+	}
+	if json.Valid(body) {
+		entry.Response = redactToken(body, token)
+	} else {
+		entry.Data = body
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.writer.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("write cassette error: %w", err)
+	}
+
+	return response, nil
+}
+
+// LoadCassette - read entries from JSONL cassette
+func LoadCassette(r io.Reader) ([]CassetteEntry, error) {
+	entries := []CassetteEntry{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		entry := CassetteEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// CassettePlayer - HttpClient which serves recorded responses without network.
+// getUpdates requests after the end of cassette wait until request is cancelled,
+// like long polling without new updates.
+type CassettePlayer struct {
+	mu      sync.Mutex
+	match   CassetteMatch
+	entries []CassetteEntry
+	used    []bool
+	next    int
+	err     error
+}
+
+// NewCassettePlayer - create player for entries
+func NewCassettePlayer(entries []CassetteEntry, match CassetteMatch) *CassettePlayer {
+	return &CassettePlayer{
+		match:   match,
+		entries: entries,
+		used:    make([]bool, len(entries)),
+	}
+}
+
+// Do - return recorded response for request
+func (p *CassettePlayer) Do(request *http.Request) (*http.Response, error) {
+	method, token := cassetteMethod(request)
+	params, err := cassetteParams(request, token)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	entry, err := p.find(method, params)
+	// Long polling after the end of cassette is expected and is not a mismatch
+	polling := errors.Is(err, ErrCassetteExhausted) && method == "getUpdates"
+	if err != nil && !polling && p.err == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+
+	if polling {
+		<-request.Context().Done()
+		return nil, request.Context().Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &http.Response{
+		Status:     fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode: entry.StatusCode,
+		Header:     http.Header{},
+		Request:    request,
+	}
+	if entry.Response != nil {
+		response.Header.Set("Content-Type", "application/json")
+		response.Body = io.NopCloser(bytes.NewReader(entry.Response))
+	} else {
+		response.Body = io.NopCloser(bytes.NewReader(entry.Data))
+	}
+
+	return response, nil
+}
+
+func (p *CassettePlayer) find(method string, params map[string]string) (*CassetteEntry, error) {
+	if p.match == CASSETTE_MATCH_STRICT {
+		if p.next >= len(p.entries) {
+			return nil, fmt.Errorf("%w: unexpected %s request", ErrCassetteExhausted, method)
+		}
+
+		entry := &p.entries[p.next]
+		if entry.Method != method || !equalParams(entry.Params, params) {
+			return nil, fmt.Errorf("%w: entry %d is %s %v, got %s %v", ErrCassetteMismatch, p.next+1, entry.Method, entry.Params, method, params)
+		}
+		p.used[p.next] = true
+		p.next++
+
+		return entry, nil
+	}
+
+	found := -1
+	for i := range p.entries {
+		if p.used[i] || p.entries[i].Method != method {
+			continue
+		}
+		if equalParams(p.entries[i].Params, params) {
+			found = i
+			break
+		}
+		if found < 0 {
+			found = i
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w: unexpected %s request", ErrCassetteExhausted, method)
+	}
+	p.used[found] = true
+
+	return &p.entries[found], nil
+}
+
+// Remaining - return number of entries not played yet
+func (p *CassettePlayer) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	remaining := 0
+	for _, used := range p.used {
+		if !used {
+			remaining++
+		}
+	}
+
+	return remaining
+}
+
+// Done - return first mismatch error or error if some entries were not played
+func (p *CassettePlayer) Done() error {
+	remaining := p.Remaining()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err == nil && remaining > 0 {
+		return fmt.Errorf("%w: %d entries were not played", ErrCassetteMismatch, remaining)
+	}
+
+	return p.err
+}
+
+// cassetteMethod - return API method and bot token from request path
+// ("/bot<token>/<method>" or "/file/bot<token>/<file path>")
+func cassetteMethod(request *http.Request) (string, string) {
+	path := strings.TrimPrefix(request.URL.Path, "/")
+	prefix := ""
+	if rest, ok := strings.CutPrefix(path, "file/"); ok {
+		path = rest
+		prefix = "file/"
+	}
+
+	bot, method, _ := strings.Cut(path, "/")
+	token, ok := strings.CutPrefix(bot, "bot")
+	if !ok {
+		return prefix + path, ""
+	}
+
+	return prefix + method, token
+}
+
+// cassetteParams - return request params as strings, JSON values are compacted.
+// Request body is restored after reading.
+func cassetteParams(request *http.Request, token string) (map[string]string, error) {
+	params := map[string]string{}
+	for key, values := range request.URL.Query() {
+		params[key] = strings.Join(values, ",")
+	}
+
+	if request.Body == nil {
+		return redactParams(params, token), nil
+	}
+
+	body, err := io.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		values := map[string]json.RawMessage{}
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := json.Unmarshal(body, &values); err != nil {
+				return nil, fmt.Errorf("decode request body error: %w", err)
+			}
+		}
+		for key, value := range values {
+			params[key] = jsonParam(value)
+		}
+
+	case "multipart/form-data":
+		clone := request.Clone(request.Context())
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+		if err := clone.ParseMultipartForm(32 << 20); err != nil {
+			return nil, fmt.Errorf("decode request body error: %w", err)
+		}
+		for key, values := range clone.MultipartForm.Value {
+			params[key] = strings.Join(values, ",")
+		}
+		for key, files := range clone.MultipartForm.File {
+			params[key] = fmt.Sprintf("<file %s, %d bytes>", files[0].Filename, files[0].Size)
+		}
+		clone.MultipartForm.RemoveAll()
+	}
+
+	return redactParams(params, token), nil
+}
+
+func jsonParam(value json.RawMessage) string {
+	s := ""
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, value); err != nil {
+		return string(value)
+	}
+
+	return buf.String()
+}
+
+func redactParams(params map[string]string, token string) map[string]string {
+	if token == "" {
+		return params
+	}
+
+	for key, value := range params {
+		params[key] = strings.ReplaceAll(value, token, REDACTED_TOKEN)
+	}
+
+	return params
+}
+
+func redactToken(data []byte, token string) []byte {
+	if token == "" {
+		return data
+	}
+
+	return bytes.ReplaceAll(data, []byte(token), []byte(REDACTED_TOKEN))
+}
+
+func equalParams(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+
+	return true
+}
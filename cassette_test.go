@@ -0,0 +1,183 @@
+package micha
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testCassetteToken = "111:secret"
+
+func newCassetteTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if bytes.Contains(body, []byte(`"chat_id":2`)) {
+			r.URL.Path = "/unknown"
+		}
+
+		switch r.URL.Path {
+		case "/bot" + testCassetteToken + "/getMe":
+			w.Write([]byte(`{"ok":true,"result":{"id":111,"is_bot":true,"first_name":"Bot"}}`))
+		case "/bot" + testCassetteToken + "/sendMessage":
+			w.Write([]byte(`{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":1,"type":"private"},"text":"hello"}}`))
+		case "/bot" + testCassetteToken + "/getFile":
+			w.Write([]byte(`{"ok":true,"result":{"file_id":"f1","file_path":"docs/a.txt"}}`))
+		case "/file/bot" + testCassetteToken + "/docs/a.txt":
+			w.Write([]byte("file content"))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+		}
+	}))
+}
+
+func recordTestCassette(t *testing.T) []byte {
+	server := newCassetteTestServer()
+	defer server.Close()
+
+	cassette := new(bytes.Buffer)
+	bot, err := NewBot(testCassetteToken, WithAPIServer(server.URL), WithHttpClient(NewCassetteRecorder(nil, cassette)))
+	require.Nil(t, err)
+
+	_, err = bot.SendMessage("1", "hello", nil)
+	require.Nil(t, err)
+	_, err = bot.SendMessage("2", "hello", nil)
+	require.NotNil(t, err)
+
+	file, err := bot.GetFile("f1")
+	require.Nil(t, err)
+	response, err := bot.httpClient.Do(httptestGet(t, bot.DownloadFileURL(file.FilePath)))
+	require.Nil(t, err)
+	response.Body.Close()
+
+	return cassette.Bytes()
+}
+
+func httptestGet(t *testing.T, url string) *http.Request {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.Nil(t, err)
+
+	return request
+}
+
+func TestCassetteRecorder(t *testing.T) {
+	cassette := recordTestCassette(t)
+	require.NotContains(t, string(cassette), "secret")
+
+	entries, err := LoadCassette(bytes.NewReader(cassette))
+	require.Nil(t, err)
+	require.Len(t, entries, 5)
+
+	require.Equal(t, "getMe", entries[0].Method)
+	require.Equal(t, "sendMessage", entries[1].Method)
+	require.Equal(t, map[string]string{"chat_id": "1", "text": "hello"}, entries[1].Params)
+	require.Equal(t, http.StatusBadRequest, entries[2].StatusCode)
+	require.Equal(t, map[string]string{"file_id": "f1"}, entries[3].Params)
+	require.Equal(t, "file/docs/a.txt", entries[4].Method)
+	require.Equal(t, []byte("file content"), entries[4].Data)
+	require.Nil(t, entries[4].Response)
+}
+
+func TestCassettePlayerStrict(t *testing.T) {
+	entries, err := LoadCassette(bytes.NewReader(recordTestCassette(t)))
+	require.Nil(t, err)
+
+	// Token differs from recorded one, it's redacted on both sides
+	player := NewCassettePlayer(entries, CASSETTE_MATCH_STRICT)
+	bot, err := NewBot("222:other", WithAPIServer("http://replay"), WithHttpClient(player))
+	require.Nil(t, err)
+
+	message, err := bot.SendMessage("1", "hello", nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), message.MessageID)
+
+	_, err = bot.SendMessage("2", "hello", nil)
+	require.Equal(t, "Error 400 (Bad Request: chat not found)", err.Error())
+
+	require.ErrorIs(t, player.Done(), ErrCassetteMismatch)
+	require.Equal(t, 2, player.Remaining())
+
+	_, err = bot.GetFile("f2")
+	require.ErrorIs(t, err, ErrCassetteMismatch)
+	require.ErrorIs(t, player.Done(), ErrCassetteMismatch)
+}
+
+func TestCassettePlayerLenient(t *testing.T) {
+	entries, err := LoadCassette(bytes.NewReader(recordTestCassette(t)))
+	require.Nil(t, err)
+
+	player := NewCassettePlayer(entries, CASSETTE_MATCH_LENIENT)
+	bot, err := NewBot(testCassetteToken, WithAPIServer("http://replay"), WithHttpClient(player))
+	require.Nil(t, err)
+
+	// Out of order, params are preferred but not required
+	file, err := bot.GetFile("other")
+	require.Nil(t, err)
+	require.Equal(t, "docs/a.txt", file.FilePath)
+
+	_, err = bot.SendMessage("2", "hello", nil)
+	require.NotNil(t, err)
+	_, err = bot.SendMessage("1", "changed", nil)
+	require.Nil(t, err)
+
+	response, err := player.Do(httptestGet(t, bot.DownloadFileURL(file.FilePath)))
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Nil(t, player.Done())
+
+	_, err = bot.SendMessage("1", "hello", nil)
+	require.ErrorIs(t, err, ErrCassetteExhausted)
+}
+
+func TestCassettePlayerLongPolling(t *testing.T) {
+	entries := []CassetteEntry{
+		{Method: "getMe", StatusCode: http.StatusOK, Response: json.RawMessage(`{"ok":true,"result":{"id":111,"is_bot":true,"first_name":"Bot"}}`)},
+		{
+			Method:     "getUpdates",
+			Params:     map[string]string{"limit": "100", "offset": "1", "timeout": "25"},
+			StatusCode: http.StatusOK,
+			Response:   json.RawMessage(`{"ok":true,"result":[{"update_id":1,"message":{"message_id":1,"date":0,"chat":{"id":1,"type":"private"},"text":"hi"}}]}`),
+		},
+		{
+			Method:     "sendMessage",
+			Params:     map[string]string{"chat_id": "1", "text": "echo: hi"},
+			StatusCode: http.StatusOK,
+			Response:   json.RawMessage(`{"ok":true,"result":{"message_id":2,"date":0,"chat":{"id":1,"type":"private"},"text":"echo: hi"}}`),
+		},
+	}
+
+	// Requests of the consumer and the next long polling request race, so order is not strict
+	player := NewCassettePlayer(entries, CASSETTE_MATCH_LENIENT)
+	bot, err := NewBot(testCassetteToken, WithAPIServer("http://replay"), WithHttpClient(player))
+	require.Nil(t, err)
+
+	go bot.Start()
+	update := <-bot.Updates()
+	_, err = bot.SendMessage(update.Message.Chat.ID, "echo: "+update.Message.Text, nil)
+	require.Nil(t, err)
+
+	// Polling after the last entry waits like long polling without new updates
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, bot.buildURL("getUpdates")+"?offset=2", nil)
+	require.Nil(t, err)
+	_, err = player.Do(request)
+	require.ErrorIs(t, err, context.Canceled)
+
+	bot.Stop()
+	for range bot.Updates() {
+	}
+	require.Nil(t, player.Done())
+}
+
+func TestLoadCassetteError(t *testing.T) {
+	_, err := LoadCassette(strings.NewReader("{\"method\":\"getMe\"}\n\nnot json\n"))
+	require.EqualError(t, err, "cassette line 3: invalid character 'o' in literal null (expecting 'u')")
+}
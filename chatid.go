@@ -0,0 +1,128 @@
+package micha
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// channelIDOffset - supergroup and channel ids are -100<channel id> in the Bot API
+const channelIDOffset = -1000000000000
+
+// ChatID - unique identifier of a chat (int64) or username of a channel or supergroup ("@channelusername").
+//
+// ChatID is a string rather than a struct on purpose: string literals like "12345" and "@channel"
+// stay valid chat ids, and omitempty works for optional chat ids (it never omits structs).
+// Use ChatIDFromInt and ChatIDFromUsername to build chat ids and Int64 to read numeric ones.
+// Numeric ids are encoded as JSON numbers in canonical form, usernames as JSON strings,
+// anything else fails to encode (see Validate), empty chat id is encoded as empty string.
+type ChatID string
+
+// ChatIDFromInt - create chat id from numeric id
+func ChatIDFromInt(id int64) ChatID {
+	return ChatID(strconv.FormatInt(id, 10))
+}
+
+// ChatIDFromUsername - create chat id from channel or supergroup username, "@" prefix is optional
+func ChatIDFromUsername(username string) ChatID {
+	return ChatID("@" + strings.TrimPrefix(username, "@"))
+}
+
+// ChatIDFromChannel - create supergroup or channel chat id from its id without -100 prefix
+// (as used in t.me/c/<channel id>/<message id> links)
+func ChatIDFromChannel(channelID int64) ChatID {
+	return ChatIDFromInt(channelIDOffset - channelID)
+}
+
+// Int64 - return numeric id, false for usernames
+func (chatID ChatID) Int64() (int64, bool) {
+	id, err := strconv.ParseInt(string(chatID), 10, 64)
+	return id, err == nil
+}
+
+// IsUsername - chat id is "@channelusername"
+func (chatID ChatID) IsUsername() bool {
+	return strings.HasPrefix(string(chatID), "@")
+}
+
+// Username - return username without "@" for username chat ids
+func (chatID ChatID) Username() (string, bool) {
+	return strings.CutPrefix(string(chatID), "@")
+}
+
+// IsUser - chat id is a private chat with user
+func (chatID ChatID) IsUser() bool {
+	id, ok := chatID.Int64()
+	return ok && id > 0
+}
+
+// IsGroup - chat id is a basic group
+func (chatID ChatID) IsGroup() bool {
+	id, ok := chatID.Int64()
+	return ok && id < 0 && id > channelIDOffset
+}
+
+// IsSupergroupOrChannel - chat id has -100 prefix of supergroups and channels
+func (chatID ChatID) IsSupergroupOrChannel() bool {
+	id, ok := chatID.Int64()
+	return ok && id < channelIDOffset
+}
+
+// ChannelID - return supergroup or channel id without -100 prefix
+func (chatID ChatID) ChannelID() (int64, bool) {
+	if !chatID.IsSupergroupOrChannel() {
+		return 0, false
+	}
+
+	id, _ := chatID.Int64()
+	return channelIDOffset - id, true
+}
+
+// Validate - check that chat id is empty, numeric or "@username"
+func (chatID ChatID) Validate() error {
+	if chatID == "" || chatID.IsUsername() {
+		return nil
+	}
+	if _, ok := chatID.Int64(); !ok {
+		return fmt.Errorf("invalid chat id %q: must be integer or @username", string(chatID))
+	}
+
+	return nil
+}
+
+func (chatID ChatID) MarshalJSON() ([]byte, error) {
+	if id, ok := chatID.Int64(); ok {
+		return strconv.AppendInt(nil, id, 10), nil
+	}
+	if err := chatID.Validate(); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(chatID))
+}
+
+func (chatID *ChatID) UnmarshalJSON(value []byte) error {
+	value = bytes.TrimSpace(value)
+	if bytes.Equal(value, []byte("null")) {
+		return nil
+	}
+
+	if len(value) > 0 && value[0] == '"' {
+		s := ""
+		if err := json.Unmarshal(value, &s); err != nil {
+			return err
+		}
+		*chatID = ChatID(s)
+		return nil
+	}
+
+	id, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat id %s", value)
+	}
+	*chatID = ChatIDFromInt(id)
+
+	return nil
+}
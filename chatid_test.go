@@ -0,0 +1,100 @@
+package micha
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatIDJSON(t *testing.T) {
+	for _, c := range []struct {
+		chatID ChatID
+		json   string
+	}{
+		{ChatIDFromInt(123), `123`},
+		{ChatIDFromInt(-1001234567890), `-1001234567890`},
+		{ChatIDFromUsername("channel"), `"@channel"`},
+		{ChatIDFromUsername("@channel"), `"@channel"`},
+		{"456", `456`},
+	} {
+		data, err := json.Marshal(c.chatID)
+		require.Nil(t, err)
+		require.Equal(t, c.json, string(data))
+
+		chatID := ChatID("")
+		require.Nil(t, json.Unmarshal(data, &chatID))
+		require.Equal(t, c.chatID, chatID)
+	}
+
+	// Numeric ids are always encoded in canonical form
+	for _, c := range []struct {
+		chatID ChatID
+		json   string
+	}{
+		{"007", `7`},
+		{"+5", `5`},
+		{"", `""`},
+	} {
+		data, err := json.Marshal(c.chatID)
+		require.Nil(t, err)
+		require.Equal(t, c.json, string(data))
+	}
+
+	_, err := json.Marshal(ChatID("channel"))
+	require.ErrorContains(t, err, `invalid chat id "channel": must be integer or @username`)
+	_, err = structToValues(stdJSONCodec{}, sendMessageParams{ChatID: "channel", Text: "hi"})
+	require.NotNil(t, err)
+	require.Nil(t, ChatID("@channel").Validate())
+
+	chatID := ChatID("")
+	require.Nil(t, json.Unmarshal([]byte(`"789"`), &chatID))
+	require.Equal(t, ChatIDFromInt(789), chatID)
+	require.NotNil(t, json.Unmarshal([]byte(`1.5`), &chatID))
+
+	values, err := structToValues(stdJSONCodec{}, sendMessageParams{ChatID: ChatIDFromUsername("channel"), Text: "hi"})
+	require.Nil(t, err)
+	require.Equal(t, "@channel", values.Get("chat_id"))
+
+	values, err = structToValues(stdJSONCodec{}, sendMessageParams{ChatID: "007", Text: "hi"})
+	require.Nil(t, err)
+	require.Equal(t, "7", values.Get("chat_id"))
+
+	values, err = structToValues(stdJSONCodec{}, sendMessageParams{ChatID: ChatIDFromUsername("channel"), Text: "hi"})
+	require.Nil(t, err)
+	require.Equal(t, "@channel", values.Get("chat_id"))
+
+	values, err = structToValues(stdJSONCodec{}, sendMessageParams{ChatID: ChatIDFromInt(-42), Text: "hi"})
+	require.Nil(t, err)
+	require.Equal(t, "-42", values.Get("chat_id"))
+}
+
+func TestChatIDKind(t *testing.T) {
+	id, ok := ChatIDFromInt(42).Int64()
+	require.True(t, ok)
+	require.Equal(t, int64(42), id)
+
+	_, ok = ChatIDFromUsername("channel").Int64()
+	require.False(t, ok)
+
+	username, ok := ChatIDFromUsername("channel").Username()
+	require.True(t, ok)
+	require.Equal(t, "channel", username)
+	require.True(t, ChatIDFromUsername("channel").IsUsername())
+
+	require.True(t, ChatIDFromInt(42).IsUser())
+	require.True(t, ChatIDFromInt(-42).IsGroup())
+	require.False(t, ChatIDFromInt(-42).IsSupergroupOrChannel())
+
+	chatID := ChatIDFromChannel(1234567890)
+	require.Equal(t, ChatID("-1001234567890"), chatID)
+	require.True(t, chatID.IsSupergroupOrChannel())
+	require.False(t, chatID.IsGroup())
+
+	channelID, ok := chatID.ChannelID()
+	require.True(t, ok)
+	require.Equal(t, int64(1234567890), channelID)
+
+	_, ok = ChatIDFromInt(-42).ChannelID()
+	require.False(t, ok)
+}
@@ -0,0 +1,31 @@
+package micha
+
+import (
+	"encoding/json"
+)
+
+// JSONCodec - JSON encoder and decoder of API requests and responses.
+// It can be replaced with WithJSONCodec by a faster implementation compatible with encoding/json
+// (struct tags, json.RawMessage, json.Marshaler and json.Unmarshaler).
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// responseEnvelope - response with result decoded directly into the target
+type responseEnvelope struct {
+	Ok          bool        `json:"ok"`
+	ErrorCode   int         `json:"error_code"`
+	Description string      `json:"description"`
+	Result      interface{} `json:"result"`
+}
@@ -0,0 +1,133 @@
+package micha
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingCodec struct {
+	stdJSONCodec
+	marshals   int
+	unmarshals int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return c.stdJSONCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals++
+	return c.stdJSONCodec.Unmarshal(data, v)
+}
+
+func TestWithJSONCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getMe"):
+			w.Write([]byte(`{"ok":true,"result":{"id":1,"first_name":"Bot"}}`))
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			body, _ := io.ReadAll(r.Body)
+			fmt.Fprintf(w, `{"ok":true,"result":{"message_id":5,"chat":{"id":1,"type":"private"},"text":%q}}`, body)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request"}`))
+		}
+	}))
+	defer server.Close()
+
+	codec := &countingCodec{}
+	bot, err := NewBot("111", WithAPIServer(server.URL), WithJSONCodec(codec))
+	require.Nil(t, err)
+	require.Equal(t, 0, codec.marshals)
+	require.Equal(t, 1, codec.unmarshals)
+
+	message, err := bot.SendMessage("1", "hi", nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(5), message.MessageID)
+	require.JSONEq(t, `{"chat_id":1,"text":"hi"}`, message.Text)
+	require.Equal(t, 1, codec.marshals)
+	require.Equal(t, 2, codec.unmarshals)
+
+	// Error responses are decoded in a single pass too
+	err = bot.post("unknown", nil, nil)
+	require.EqualError(t, err, "Error 400 (Bad Request)")
+	require.Equal(t, 3, codec.unmarshals)
+}
+
+func TestUpdateSourcesJSONCodec(t *testing.T) {
+	codec := &countingCodec{}
+	bot := &Bot{Options: Options{jsonCodec: codec}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan Update, 1)
+
+	source := bot.WebhookSource("")
+	go source.Run(ctx, updates)
+	recorder := httptest.NewRecorder()
+	source.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"update_id":1}`)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, uint64(1), (<-updates).UpdateID)
+	require.Equal(t, 1, codec.unmarshals)
+
+	replayer := NewUpdateReplayer(strings.NewReader("{\"update_id\":2}\n{\"update_id\":3}\n"), WithReplayJSONCodec(codec))
+	replayed := make(chan Update, 2)
+	require.Nil(t, replayer.Run(ctx, replayed))
+	require.Len(t, replayed, 2)
+	require.Equal(t, 3, codec.unmarshals)
+}
+
+const benchmarkUpdate = `{"update_id":%d,"message":{"message_id":%d,"from":{"id":42,"is_bot":false,"first_name":"Alice","username":"alice","language_code":"en"},"date":1700000000,"chat":{"id":42,"type":"private","first_name":"Alice","username":"alice"},"text":"/start payload with some text","entities":[{"type":"bot_command","offset":0,"length":6}]}}`
+
+func benchmarkUpdatesResponse(n int) []byte {
+	updates := make([]string, n)
+	for i := range updates {
+		updates[i] = fmt.Sprintf(benchmarkUpdate, i+1, i+1)
+	}
+
+	return []byte(`{"ok":true,"result":[` + strings.Join(updates, ",") + `]}`)
+}
+
+func BenchmarkDecodeUpdates(b *testing.B) {
+	bot := &Bot{Options: Options{jsonCodec: stdJSONCodec{}}}
+	data := benchmarkUpdatesResponse(100)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		updates := []Update{}
+		if err := bot.decodeResponse(data, &updates); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeSendMessage(b *testing.B) {
+	params := sendMessageParams{
+		ChatID: "42",
+		Text:   "Hello, world! This is a message with a keyboard",
+		SendMessageOptions: SendMessageOptions{
+			ParseMode: PARSE_MODE_HTML,
+			ReplyMarkup: &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+				{{Text: "Yes", CallbackData: "yes"}, {Text: "No", CallbackData: "no"}},
+				{{Text: "Site", URL: "https://example.com"}},
+			}},
+		},
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := newPostRequest(context.Background(), stdJSONCodec{}, "http://localhost/bot/sendMessage", params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
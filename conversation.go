@@ -0,0 +1,448 @@
+package micha
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownConversationState = errors.New("unknown conversation state")
+	ErrConversationLoop         = errors.New("conversation loop")
+)
+
+// ConversationState - persisted state of a conversation
+type ConversationState struct {
+	Name      string            `json:"name"`
+	Stack     []string          `json:"stack,omitempty"` // Parent states of nested sub-dialogs
+	Data      map[string]string `json:"data,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// ConversationStorage persists conversation states between updates and restarts.
+// Load must return nil state without error if there is no conversation for the key.
+type ConversationStorage interface {
+	Load(key string) (*ConversationState, error)
+	Save(key string, state *ConversationState) error
+	Delete(key string) error
+}
+
+// MemoryConversationStorage - in-memory ConversationStorage
+type MemoryConversationStorage struct {
+	mu     sync.Mutex
+	states map[string]ConversationState
+}
+
+// NewMemoryConversationStorage - create new in-memory conversation storage
+func NewMemoryConversationStorage() *MemoryConversationStorage {
+	return &MemoryConversationStorage{
+		states: map[string]ConversationState{},
+	}
+}
+
+func (s *MemoryConversationStorage) Load(key string) (*ConversationState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return state.copy(), nil
+}
+
+func (s *MemoryConversationStorage) Save(key string, state *ConversationState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[key] = *state.copy()
+	return nil
+}
+
+func (s *MemoryConversationStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, key)
+	return nil
+}
+
+func (s *ConversationState) copy() *ConversationState {
+	state := *s
+	state.Stack = append([]string(nil), s.Stack...)
+	state.Data = make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		state.Data[k] = v
+	}
+
+	return &state
+}
+
+// ConversationHandler - handler of conversation events
+type ConversationHandler func(c *ConversationContext) error
+
+// ConversationStep describes one named state of a conversation
+type ConversationStep struct {
+	// Called when conversation enters the state, e.g. to ask a question.
+	// Also called to re-prompt when Validate fails (ConversationContext.Err is set).
+	Enter ConversationHandler
+
+	// Called before Handle, returned error rejects the update and re-prompts.
+	// Validate and re-prompting Enter can move conversation to other state.
+	Validate ConversationHandler
+
+	// Called with valid update, use ConversationContext methods to move to other state
+	Handle ConversationHandler
+
+	// Called instead of Enter when nested sub-dialog started from this state returns
+	Resume ConversationHandler
+
+	// Overrides conversation timeout for this state
+	Timeout time.Duration
+}
+
+type conversationAction int
+
+const (
+	conversationStay conversationAction = iota
+	conversationTransition
+	conversationBegin
+	conversationReturn
+	conversationEnd
+)
+
+// ConversationContext - context of a conversation handler
+type ConversationContext struct {
+	Update Update
+	State  *ConversationState
+	Err    error // Validation error when re-prompting
+
+	action conversationAction
+	next   string
+}
+
+// Message - return message or callback query message of the update
+func (c *ConversationContext) Message() *Message {
+	switch {
+	case c.Update.Message != nil:
+		return c.Update.Message
+	case c.Update.CallbackQuery != nil:
+		return c.Update.CallbackQuery.Message
+	}
+
+	return nil
+}
+
+// Text - return message text or callback query data
+func (c *ConversationContext) Text() string {
+	switch {
+	case c.Update.Message != nil:
+		return c.Update.Message.Text
+	case c.Update.CallbackQuery != nil:
+		return c.Update.CallbackQuery.Data
+	}
+
+	return ""
+}
+
+// Get - return conversation value
+func (c *ConversationContext) Get(key string) string {
+	return c.State.Data[key]
+}
+
+// Set - store conversation value
+func (c *ConversationContext) Set(key, value string) {
+	if c.State.Data == nil {
+		c.State.Data = map[string]string{}
+	}
+	c.State.Data[key] = value
+}
+
+// Transition - move conversation to state
+func (c *ConversationContext) Transition(state string) {
+	c.action, c.next = conversationTransition, state
+}
+
+// Begin - start nested sub-dialog with state, Return moves back to the current state
+func (c *ConversationContext) Begin(state string) {
+	c.action, c.next = conversationBegin, state
+}
+
+// Return - finish nested sub-dialog, ends conversation if there is no parent state
+func (c *ConversationContext) Return() {
+	c.action, c.next = conversationReturn, ""
+}
+
+// End - finish conversation
+func (c *ConversationContext) End() {
+	c.action, c.next = conversationEnd, ""
+}
+
+// Conversation - multi-step dialog with a user in a chat.
+// Feed it with updates via HandleUpdate, timeouts are checked when the next update arrives.
+type Conversation struct {
+	storage        ConversationStorage
+	steps          map[string]ConversationStep
+	timeout        time.Duration
+	cancelCommands []string
+	onCancel       ConversationHandler
+	onTimeout      ConversationHandler
+	now            func() time.Time
+	locks          conversationLocks
+}
+
+// Max number of state changes made by Enter handlers for one update
+const maxConversationEnterDepth = 16
+
+// conversationLocks - per key mutexes, so updates of one conversation are handled one by one
+type conversationLocks struct {
+	mu    sync.Mutex
+	locks map[string]*conversationLock
+}
+
+type conversationLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock - lock the key, returns unlock function
+func (l *conversationLocks) lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*conversationLock{}
+	}
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &conversationLock{}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// NewConversation - create new conversation
+func NewConversation(storage ConversationStorage) *Conversation {
+	return &Conversation{
+		storage: storage,
+		steps:   map[string]ConversationStep{},
+		now:     time.Now,
+	}
+}
+
+// Step - register state
+func (c *Conversation) Step(name string, step ConversationStep) *Conversation {
+	c.steps[name] = step
+	return c
+}
+
+// Timeout - end conversations inactive for timeout, handler is called with the update that came too late
+func (c *Conversation) Timeout(timeout time.Duration, handler ConversationHandler) *Conversation {
+	c.timeout = timeout
+	c.onTimeout = handler
+	return c
+}
+
+// Cancel - end conversation on commands (e.g. "/cancel")
+func (c *Conversation) Cancel(handler ConversationHandler, commands ...string) *Conversation {
+	c.onCancel = handler
+	c.cancelCommands = commands
+	return c
+}
+
+// Start - start conversation in state for the chat and user of the update
+func (c *Conversation) Start(update Update, state string) error {
+	key, ok := conversationKey(update)
+	if !ok {
+		return fmt.Errorf("conversation: update has no chat or user")
+	}
+	defer c.locks.lock(key)()
+
+	ctx := &ConversationContext{
+		Update: update,
+		State:  &ConversationState{},
+	}
+	ctx.Transition(state)
+
+	return c.apply(key, ctx, 0)
+}
+
+// Active - check if conversation is in progress for the chat and user of the update
+func (c *Conversation) Active(update Update) (bool, error) {
+	key, ok := conversationKey(update)
+	if !ok {
+		return false, nil
+	}
+
+	state, err := c.storage.Load(key)
+	return state != nil, err
+}
+
+// HandleUpdate - process update of an active conversation.
+// Updates of the same chat and user are handled one at a time.
+// Returns true if update was consumed by the conversation.
+func (c *Conversation) HandleUpdate(update Update) (bool, error) {
+	key, ok := conversationKey(update)
+	if !ok {
+		return false, nil
+	}
+	defer c.locks.lock(key)()
+
+	state, err := c.storage.Load(key)
+	if err != nil || state == nil {
+		return false, err
+	}
+
+	ctx := &ConversationContext{
+		Update: update,
+		State:  state,
+	}
+
+	step, ok := c.steps[state.Name]
+	if !ok {
+		return true, fmt.Errorf("%w: %s", ErrUnknownConversationState, state.Name)
+	}
+
+	timeout := c.timeout
+	if step.Timeout > 0 {
+		timeout = step.Timeout
+	}
+	if timeout > 0 && c.now().Sub(state.UpdatedAt) > timeout {
+		if err := c.storage.Delete(key); err != nil {
+			return false, err
+		}
+
+		// Late update is not a part of the conversation anymore
+		return false, c.call(c.onTimeout, ctx)
+	}
+
+	if c.isCancel(ctx.Text()) {
+		if err := c.storage.Delete(key); err != nil {
+			return true, err
+		}
+
+		return true, c.call(c.onCancel, ctx)
+	}
+
+	if step.Validate != nil {
+		if ctx.Err = step.Validate(ctx); ctx.Err != nil {
+			if err := c.call(step.Enter, ctx); err != nil {
+				return true, err
+			}
+
+			// Validate or Enter may move conversation, e.g. after too many attempts
+			return true, c.apply(key, ctx, 0)
+		}
+	}
+
+	if err := c.call(step.Handle, ctx); err != nil {
+		return true, err
+	}
+
+	return true, c.apply(key, ctx, 0)
+}
+
+// apply - perform action of the handler, depth is number of state changes made by Enter handlers
+func (c *Conversation) apply(key string, ctx *ConversationContext, depth int) error {
+	state := ctx.State
+	var enter ConversationHandler
+
+	switch ctx.action {
+	case conversationTransition, conversationBegin:
+		step, ok := c.steps[ctx.next]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownConversationState, ctx.next)
+		}
+		if ctx.action == conversationBegin {
+			state.Stack = append(state.Stack, state.Name)
+		}
+		state.Name = ctx.next
+		enter = step.Enter
+
+	case conversationReturn:
+		if len(state.Stack) == 0 {
+			return c.storage.Delete(key)
+		}
+		state.Name = state.Stack[len(state.Stack)-1]
+		state.Stack = state.Stack[:len(state.Stack)-1]
+
+		step, ok := c.steps[state.Name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownConversationState, state.Name)
+		}
+		enter = step.Resume
+		if enter == nil {
+			enter = step.Enter
+		}
+
+	case conversationEnd:
+		return c.storage.Delete(key)
+	}
+
+	if enter != nil {
+		// Enter handler can move conversation further, e.g. skip optional step
+		next := &ConversationContext{Update: ctx.Update, State: state}
+		if err := enter(next); err != nil {
+			return err
+		}
+		if next.action != conversationStay {
+			if (next.action == conversationTransition && next.next == state.Name) || depth >= maxConversationEnterDepth {
+				return fmt.Errorf("%w: enter handler of %s moved conversation to %s", ErrConversationLoop, state.Name, next.next)
+			}
+			return c.apply(key, next, depth+1)
+		}
+	}
+
+	return c.save(key, state)
+}
+
+func (c *Conversation) save(key string, state *ConversationState) error {
+	state.UpdatedAt = c.now()
+	return c.storage.Save(key, state)
+}
+
+func (c *Conversation) call(handler ConversationHandler, ctx *ConversationContext) error {
+	if handler == nil {
+		return nil
+	}
+
+	return handler(ctx)
+}
+
+func (c *Conversation) isCancel(text string) bool {
+	command, _, _ := strings.Cut(text, " ")
+	command, _, _ = strings.Cut(command, "@")
+	for _, cancel := range c.cancelCommands {
+		if command == cancel {
+			return true
+		}
+	}
+
+	return false
+}
+
+func conversationKey(update Update) (string, bool) {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		return fmt.Sprintf("%s:%d", update.Message.Chat.ID, update.Message.From.ID), true
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		return fmt.Sprintf("%s:%d", update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.From.ID), true
+	}
+
+	return "", false
+}
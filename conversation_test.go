@@ -0,0 +1,252 @@
+package micha
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testConversationUpdate(text string) Update {
+	return Update{Message: &Message{
+		Text: text,
+		Chat: Chat{ID: "1"},
+		From: &User{ID: 2},
+	}}
+}
+
+func TestConversation(t *testing.T) {
+	prompts := []string{}
+	prompt := func(text string) ConversationHandler {
+		return func(c *ConversationContext) error {
+			if c.Err != nil {
+				text = c.Err.Error() + ", " + text
+			}
+			prompts = append(prompts, text)
+			return nil
+		}
+	}
+
+	storage := NewMemoryConversationStorage()
+	conversation := NewConversation(storage).
+		Step("name", ConversationStep{
+			Enter: prompt("name?"),
+			Handle: func(c *ConversationContext) error {
+				c.Set("name", c.Text())
+				c.Transition("phone")
+				return nil
+			},
+		}).
+		Step("phone", ConversationStep{
+			Enter: prompt("phone?"),
+			Validate: func(c *ConversationContext) error {
+				if strings.Trim(c.Text(), "+0123456789") != "" {
+					return errors.New("invalid phone")
+				}
+				return nil
+			},
+			Handle: func(c *ConversationContext) error {
+				c.Set("phone", c.Text())
+				c.Transition("confirm")
+				return nil
+			},
+		}).
+		Step("confirm", ConversationStep{
+			Enter:  prompt("confirm?"),
+			Resume: prompt("confirm again?"),
+			Handle: func(c *ConversationContext) error {
+				if c.Text() == "edit" {
+					c.Begin("phone_edit")
+				} else {
+					c.End()
+				}
+				return nil
+			},
+		}).
+		Step("phone_edit", ConversationStep{
+			Enter: prompt("new phone?"),
+			Handle: func(c *ConversationContext) error {
+				c.Set("phone", c.Text())
+				c.Return()
+				return nil
+			},
+		})
+
+	handled, err := conversation.HandleUpdate(testConversationUpdate("hello"))
+	require.Nil(t, err)
+	require.False(t, handled)
+
+	require.Nil(t, conversation.Start(testConversationUpdate("/start"), "name"))
+	for _, text := range []string{"John", "abc", "+123", "edit", "+456"} {
+		handled, err = conversation.HandleUpdate(testConversationUpdate(text))
+		require.Nil(t, err)
+		require.True(t, handled)
+	}
+
+	state, err := storage.Load("1:2")
+	require.Nil(t, err)
+	require.Equal(t, "confirm", state.Name)
+	require.Empty(t, state.Stack)
+	require.Equal(t, map[string]string{"name": "John", "phone": "+456"}, state.Data)
+
+	handled, err = conversation.HandleUpdate(testConversationUpdate("ok"))
+	require.Nil(t, err)
+	require.True(t, handled)
+
+	active, err := conversation.Active(testConversationUpdate(""))
+	require.Nil(t, err)
+	require.False(t, active)
+
+	require.Equal(t, []string{
+		"name?",
+		"phone?",
+		"invalid phone, phone?",
+		"confirm?",
+		"new phone?",
+		"confirm again?",
+	}, prompts)
+}
+
+func TestConversationCancelAndTimeout(t *testing.T) {
+	events := []string{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	conversation := NewConversation(NewMemoryConversationStorage()).
+		Step("name", ConversationStep{
+			Handle: func(c *ConversationContext) error {
+				events = append(events, "handle "+c.Text())
+				return nil
+			},
+		}).
+		Cancel(func(c *ConversationContext) error {
+			events = append(events, "cancel")
+			return nil
+		}, "/cancel").
+		Timeout(time.Minute, func(c *ConversationContext) error {
+			events = append(events, "timeout "+c.Text())
+			return nil
+		})
+	conversation.now = func() time.Time { return now }
+
+	require.Nil(t, conversation.Start(testConversationUpdate("/start"), "name"))
+	handled, err := conversation.HandleUpdate(testConversationUpdate("/cancel@bot"))
+	require.Nil(t, err)
+	require.True(t, handled)
+
+	require.Nil(t, conversation.Start(testConversationUpdate("/start"), "name"))
+	now = now.Add(30 * time.Second)
+	handled, err = conversation.HandleUpdate(testConversationUpdate("first"))
+	require.Nil(t, err)
+	require.True(t, handled)
+
+	now = now.Add(2 * time.Minute)
+	handled, err = conversation.HandleUpdate(testConversationUpdate("late"))
+	require.Nil(t, err)
+	require.False(t, handled)
+
+	require.Equal(t, []string{"cancel", "handle first", "timeout late"}, events)
+
+	require.ErrorIs(t, conversation.Start(testConversationUpdate("/start"), "unknown"), ErrUnknownConversationState)
+}
+
+func TestConversationConcurrentUpdates(t *testing.T) {
+	storage := NewMemoryConversationStorage()
+	conversation := NewConversation(storage).
+		Step("count", ConversationStep{
+			Handle: func(c *ConversationContext) error {
+				n, _ := strconv.Atoi(c.Get("n"))
+				c.Set("n", strconv.Itoa(n+1))
+				return nil
+			},
+		})
+	require.Nil(t, conversation.Start(testConversationUpdate("/start"), "count"))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := conversation.HandleUpdate(testConversationUpdate("+1"))
+			require.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	state, err := storage.Load("1:2")
+	require.Nil(t, err)
+	require.Equal(t, "50", state.Data["n"])
+	require.Empty(t, conversation.locks.locks)
+}
+
+func TestConversationEnterLoop(t *testing.T) {
+	transition := func(state string) ConversationHandler {
+		return func(c *ConversationContext) error {
+			c.Transition(state)
+			return nil
+		}
+	}
+
+	conversation := NewConversation(NewMemoryConversationStorage()).
+		Step("self", ConversationStep{Enter: transition("self")}).
+		Step("ping", ConversationStep{Enter: transition("pong")}).
+		Step("pong", ConversationStep{Enter: transition("ping")})
+
+	require.ErrorIs(t, conversation.Start(testConversationUpdate("/start"), "self"), ErrConversationLoop)
+	require.ErrorIs(t, conversation.Start(testConversationUpdate("/start"), "ping"), ErrConversationLoop)
+}
+
+func TestConversationValidationTransition(t *testing.T) {
+	events := []string{}
+	storage := NewMemoryConversationStorage()
+	conversation := NewConversation(storage).
+		Step("code", ConversationStep{
+			Enter: func(c *ConversationContext) error {
+				if c.Err == nil {
+					events = append(events, "code?")
+					return nil
+				}
+
+				attempts, _ := strconv.Atoi(c.Get("attempts"))
+				c.Set("attempts", strconv.Itoa(attempts+1))
+				if attempts+1 >= 3 {
+					c.Transition("cancelled")
+					return nil
+				}
+				events = append(events, c.Err.Error())
+				return nil
+			},
+			Validate: func(c *ConversationContext) error {
+				if c.Text() != "1234" {
+					return errors.New("wrong code")
+				}
+				return nil
+			},
+			Handle: func(c *ConversationContext) error {
+				c.End()
+				return nil
+			},
+		}).
+		Step("cancelled", ConversationStep{
+			Enter: func(c *ConversationContext) error {
+				events = append(events, "cancelled")
+				c.End()
+				return nil
+			},
+		})
+
+	require.Nil(t, conversation.Start(testConversationUpdate("/start"), "code"))
+	for i := 0; i < 3; i++ {
+		handled, err := conversation.HandleUpdate(testConversationUpdate("0000"))
+		require.Nil(t, err)
+		require.True(t, handled)
+	}
+
+	require.Equal(t, []string{"code?", "wrong code", "wrong code", "cancelled"}, events)
+	state, err := storage.Load("1:2")
+	require.Nil(t, err)
+	require.Nil(t, state)
+}
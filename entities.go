@@ -0,0 +1,852 @@
+package micha
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// EntityText is a message entity with the text it covers
+type EntityText struct {
+	MessageEntity
+	Text string
+}
+
+func toUTF16(text string) []uint16 {
+	return utf16.Encode([]rune(text))
+}
+
+func fromUTF16(units []uint16) string {
+	return string(utf16.Decode(units))
+}
+
+// utf16Len - length of the text in UTF-16 code units as counted by Telegram
+func utf16Len(text string) int {
+	n := 0
+	for _, r := range text {
+		if r >= 0x10000 {
+			// Encoded as a surrogate pair
+			n += 2
+		} else {
+			n++
+		}
+	}
+
+	return n
+}
+
+func clampSpan(size, offset, length int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > size {
+		offset = size
+	}
+	end := offset + length
+	if end > size {
+		end = size
+	}
+	if end < offset {
+		end = offset
+	}
+
+	return offset, end
+}
+
+// End - offset of the first UTF-16 code unit after the entity
+func (e MessageEntity) End() int {
+	return e.Offset + e.Length
+}
+
+// Substring - return the part of text covered by the entity.
+// Offset and length are counted in UTF-16 code units.
+func (e MessageEntity) Substring(text string) string {
+	units := toUTF16(text)
+	start, end := clampSpan(len(units), e.Offset, e.Length)
+
+	return fromUTF16(units[start:end])
+}
+
+// ParseEntities - return entities along with the text they cover, ordered by offset (outer entities first)
+func ParseEntities(text string, entities []MessageEntity) []EntityText {
+	units := toUTF16(text)
+	result := make([]EntityText, 0, len(entities))
+	for _, entity := range sortEntities(entities) {
+		start, end := clampSpan(len(units), entity.Offset, entity.Length)
+		result = append(result, EntityText{
+			MessageEntity: entity,
+			Text:          fromUTF16(units[start:end]),
+		})
+	}
+
+	return result
+}
+
+// ParseEntities - return text entities with the text they cover
+func (m Message) ParseEntities() []EntityText {
+	return ParseEntities(m.Text, m.Entities)
+}
+
+// ParseCaptionEntities - return caption entities with the text they cover
+func (m Message) ParseCaptionEntities() []EntityText {
+	return ParseEntities(m.Caption, m.CaptionEntities)
+}
+
+// TextHTML - render message text with entities as HTML
+func (m Message) TextHTML() string {
+	return RenderHTML(m.Text, m.Entities)
+}
+
+// TextMarkdownV2 - render message text with entities as MarkdownV2
+func (m Message) TextMarkdownV2() string {
+	return RenderMarkdownV2(m.Text, m.Entities)
+}
+
+// CaptionHTML - render message caption with entities as HTML
+func (m Message) CaptionHTML() string {
+	return RenderHTML(m.Caption, m.CaptionEntities)
+}
+
+// CaptionMarkdownV2 - render message caption with entities as MarkdownV2
+func (m Message) CaptionMarkdownV2() string {
+	return RenderMarkdownV2(m.Caption, m.CaptionEntities)
+}
+
+func sortEntities(entities []MessageEntity) []MessageEntity {
+	sorted := append([]MessageEntity(nil), entities...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Offset != sorted[j].Offset {
+			return sorted[i].Offset < sorted[j].Offset
+		}
+		if sorted[i].Length != sorted[j].Length {
+			return sorted[i].Length > sorted[j].Length
+		}
+		// Code and pre can't contain other entities, so they are nested innermost
+		return !isCodeEntity(sorted[i:i+1]) && isCodeEntity(sorted[j:j+1])
+	})
+
+	return sorted
+}
+
+// entityRenderer describes markup for one parse mode
+type entityRenderer interface {
+	open(entity MessageEntity) string
+	close(entity MessageEntity) string
+	text(text string, active []MessageEntity) string
+}
+
+// RenderHTML - render text with entities using Telegram HTML markup.
+// Overlapping entities are split so the resulting tags are properly nested.
+func RenderHTML(text string, entities []MessageEntity) string {
+	return renderEntities(text, entities, htmlRenderer{})
+}
+
+// RenderMarkdownV2 - render text with entities using Telegram MarkdownV2 markup.
+// Overlapping entities are split so the resulting markup is properly nested.
+func RenderMarkdownV2(text string, entities []MessageEntity) string {
+	return renderEntities(text, entities, markdownV2Renderer{})
+}
+
+func renderEntities(text string, entities []MessageEntity, renderer entityRenderer) string {
+	units := toUTF16(text)
+	sorted := []MessageEntity{}
+	for _, entity := range sortEntities(entities) {
+		if !isFormattingEntity(entity.Type) {
+			continue
+		}
+		start, end := clampSpan(len(units), entity.Offset, entity.Length)
+		if start == end {
+			continue
+		}
+		entity.Offset, entity.Length = start, end-start
+		sorted = append(sorted, entity)
+	}
+
+	boundaries := map[int]bool{0: true, len(units): true}
+	for _, entity := range sorted {
+		boundaries[entity.Offset] = true
+		boundaries[entity.End()] = true
+	}
+	points := make([]int, 0, len(boundaries))
+	for point := range boundaries {
+		points = append(points, point)
+	}
+	sort.Ints(points)
+
+	result := strings.Builder{}
+	stack := []MessageEntity{}
+	for i := 0; i < len(points)-1; i++ {
+		start, end := points[i], points[i+1]
+		active := []MessageEntity{}
+		for _, entity := range sorted {
+			if entity.Offset <= start && entity.End() >= end {
+				active = append(active, entity)
+			}
+		}
+		// Code and pre can't contain other entities, so they are opened last
+		sort.SliceStable(active, func(i, j int) bool {
+			return !isCodeEntity(active[i:i+1]) && isCodeEntity(active[j:j+1])
+		})
+
+		// Close entities which are not active anymore, reopening the ones above them
+		keep := 0
+		for keep < len(stack) && containsEntity(active, stack[keep]) {
+			keep++
+		}
+		for j := len(stack) - 1; j >= keep; j-- {
+			result.WriteString(renderer.close(stack[j]))
+		}
+		stack = stack[:keep]
+
+		for _, entity := range active {
+			// Entities starting inside code or pre are dropped until it's closed
+			if !containsEntity(stack, entity) && !isCodeEntity(stack) {
+				result.WriteString(renderer.open(entity))
+				stack = append(stack, entity)
+			}
+		}
+
+		result.WriteString(renderer.text(fromUTF16(units[start:end]), stack))
+	}
+
+	for j := len(stack) - 1; j >= 0; j-- {
+		result.WriteString(renderer.close(stack[j]))
+	}
+
+	return result.String()
+}
+
+func containsEntity(entities []MessageEntity, entity MessageEntity) bool {
+	for i := range entities {
+		if entities[i].Type == entity.Type &&
+			entities[i].Offset == entity.Offset &&
+			entities[i].Length == entity.Length &&
+			entities[i].URL == entity.URL &&
+			entities[i].Language == entity.Language &&
+			entities[i].CustomEmojiID == entity.CustomEmojiID &&
+			entities[i].User == entity.User {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isFormattingEntity(entityType MessageEntityType) bool {
+	switch entityType {
+	case MESSAGE_ENTITY_BOLD, MESSAGE_ENTITY_ITALIC, MESSAGE_ENTITY_UNDERLINE, MESSAGE_ENTITY_STRIKETHROUGH,
+		MESSAGE_ENTITY_SPOILER, MESSAGE_ENTITY_CODE, MESSAGE_ENTITY_PRE, MESSAGE_ENTITY_TEXT_LINK,
+		MESSAGE_ENTITY_TEXT_MENTION, MESSAGE_ENTITY_CUSTOM_EMOJI, MESSAGE_ENTITY_BLOCKQUOTE,
+		MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE:
+		return true
+	}
+
+	return false
+}
+
+func isCodeEntity(entities []MessageEntity) bool {
+	for _, entity := range entities {
+		if entity.Type == MESSAGE_ENTITY_CODE || entity.Type == MESSAGE_ENTITY_PRE {
+			return true
+		}
+	}
+
+	return false
+}
+
+type htmlRenderer struct{}
+
+func (htmlRenderer) open(entity MessageEntity) string {
+	switch entity.Type {
+	case MESSAGE_ENTITY_BOLD:
+		return "<b>"
+	case MESSAGE_ENTITY_ITALIC:
+		return "<i>"
+	case MESSAGE_ENTITY_UNDERLINE:
+		return "<u>"
+	case MESSAGE_ENTITY_STRIKETHROUGH:
+		return "<s>"
+	case MESSAGE_ENTITY_SPOILER:
+		return "<tg-spoiler>"
+	case MESSAGE_ENTITY_CODE:
+		return "<code>"
+	case MESSAGE_ENTITY_PRE:
+		if entity.Language != "" {
+			return fmt.Sprintf(`<pre><code class="language-%s">`, html.EscapeString(entity.Language))
+		}
+		return "<pre>"
+	case MESSAGE_ENTITY_TEXT_LINK:
+		return fmt.Sprintf(`<a href="%s">`, html.EscapeString(entity.URL))
+	case MESSAGE_ENTITY_TEXT_MENTION:
+		if entity.User != nil {
+			return fmt.Sprintf(`<a href="tg://user?id=%d">`, entity.User.ID)
+		}
+	case MESSAGE_ENTITY_CUSTOM_EMOJI:
+		return fmt.Sprintf(`<tg-emoji emoji-id="%s">`, html.EscapeString(entity.CustomEmojiID))
+	case MESSAGE_ENTITY_BLOCKQUOTE:
+		return "<blockquote>"
+	case MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE:
+		return "<blockquote expandable>"
+	}
+
+	return ""
+}
+
+func (htmlRenderer) close(entity MessageEntity) string {
+	switch entity.Type {
+	case MESSAGE_ENTITY_BOLD:
+		return "</b>"
+	case MESSAGE_ENTITY_ITALIC:
+		return "</i>"
+	case MESSAGE_ENTITY_UNDERLINE:
+		return "</u>"
+	case MESSAGE_ENTITY_STRIKETHROUGH:
+		return "</s>"
+	case MESSAGE_ENTITY_SPOILER:
+		return "</tg-spoiler>"
+	case MESSAGE_ENTITY_CODE:
+		return "</code>"
+	case MESSAGE_ENTITY_PRE:
+		if entity.Language != "" {
+			return "</code></pre>"
+		}
+		return "</pre>"
+	case MESSAGE_ENTITY_TEXT_LINK:
+		return "</a>"
+	case MESSAGE_ENTITY_TEXT_MENTION:
+		if entity.User != nil {
+			return "</a>"
+		}
+	case MESSAGE_ENTITY_CUSTOM_EMOJI:
+		return "</tg-emoji>"
+	case MESSAGE_ENTITY_BLOCKQUOTE, MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE:
+		return "</blockquote>"
+	}
+
+	return ""
+}
+
+func (htmlRenderer) text(text string, active []MessageEntity) string {
+	return EscapeHTML(text)
+}
+
+type markdownV2Renderer struct{}
+
+func (markdownV2Renderer) open(entity MessageEntity) string {
+	switch entity.Type {
+	case MESSAGE_ENTITY_BOLD:
+		return "*"
+	case MESSAGE_ENTITY_ITALIC:
+		return "_"
+	case MESSAGE_ENTITY_UNDERLINE:
+		return "__"
+	case MESSAGE_ENTITY_STRIKETHROUGH:
+		return "~"
+	case MESSAGE_ENTITY_SPOILER:
+		return "||"
+	case MESSAGE_ENTITY_CODE:
+		return "`"
+	case MESSAGE_ENTITY_PRE:
+		return "```" + entity.Language + "\n"
+	case MESSAGE_ENTITY_TEXT_LINK, MESSAGE_ENTITY_TEXT_MENTION:
+		return "["
+	case MESSAGE_ENTITY_CUSTOM_EMOJI:
+		return "!["
+	case MESSAGE_ENTITY_BLOCKQUOTE:
+		return ">"
+	case MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE:
+		return "**>"
+	}
+
+	return ""
+}
+
+func (markdownV2Renderer) close(entity MessageEntity) string {
+	switch entity.Type {
+	case MESSAGE_ENTITY_BOLD:
+		return "*"
+	case MESSAGE_ENTITY_ITALIC:
+		// \r is ignored by Telegram and separates italic end from an underline end
+		return "_\r"
+	case MESSAGE_ENTITY_UNDERLINE:
+		return "__"
+	case MESSAGE_ENTITY_STRIKETHROUGH:
+		return "~"
+	case MESSAGE_ENTITY_SPOILER:
+		return "||"
+	case MESSAGE_ENTITY_CODE:
+		return "`"
+	case MESSAGE_ENTITY_PRE:
+		return "\n```"
+	case MESSAGE_ENTITY_TEXT_LINK:
+		return "](" + escapeMarkdownV2URL(entity.URL) + ")"
+	case MESSAGE_ENTITY_TEXT_MENTION:
+		if entity.User != nil {
+			return fmt.Sprintf("](tg://user?id=%d)", entity.User.ID)
+		}
+		return "]()"
+	case MESSAGE_ENTITY_CUSTOM_EMOJI:
+		return "](tg://emoji?id=" + escapeMarkdownV2URL(entity.CustomEmojiID) + ")"
+	case MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE:
+		return "||"
+	}
+
+	return ""
+}
+
+func (markdownV2Renderer) text(text string, active []MessageEntity) string {
+	if isCodeEntity(active) {
+		text = escapeMarkdownV2Code(text)
+	} else {
+		text = EscapeMarkdownV2(text)
+	}
+
+	for _, entity := range active {
+		if entity.Type == MESSAGE_ENTITY_BLOCKQUOTE || entity.Type == MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE {
+			// Every line of a quotation must start with >
+			text = strings.ReplaceAll(text, "\n", "\n>")
+			break
+		}
+	}
+
+	return text
+}
+
+var (
+	htmlEscaper = strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+	markdownV2Escaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+		"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+		"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+	)
+	markdownV2CodeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+	markdownV2URLEscaper  = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+)
+
+// EscapeHTML - escape text for HTML parse mode
+func EscapeHTML(text string) string {
+	return htmlEscaper.Replace(text)
+}
+
+// EscapeMarkdownV2 - escape text for MarkdownV2 parse mode
+func EscapeMarkdownV2(text string) string {
+	return markdownV2Escaper.Replace(text)
+}
+
+func escapeMarkdownV2Code(text string) string {
+	return markdownV2CodeEscaper.Replace(text)
+}
+
+func escapeMarkdownV2URL(text string) string {
+	return markdownV2URLEscaper.Replace(text)
+}
+
+type htmlTag struct {
+	name   string
+	entity *MessageEntity
+}
+
+// ParseHTML - convert text with Telegram HTML markup into plain text and entities
+func ParseHTML(markup string) (string, []MessageEntity, error) {
+	result := strings.Builder{}
+	length := 0
+	entities := []MessageEntity{}
+	stack := []htmlTag{}
+
+	for i := 0; i < len(markup); {
+		switch markup[i] {
+		case '<':
+			end := strings.IndexByte(markup[i:], '>')
+			if end < 0 {
+				return "", nil, fmt.Errorf("unclosed tag at byte %d", i)
+			}
+			tag := markup[i+1 : i+end]
+			i += end + 1
+
+			if strings.HasPrefix(tag, "/") {
+				name := strings.ToLower(strings.TrimSpace(tag[1:]))
+				if len(stack) == 0 || stack[len(stack)-1].name != name {
+					return "", nil, fmt.Errorf("unexpected end tag </%s>", name)
+				}
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if top.entity != nil {
+					top.entity.Length = length - top.entity.Offset
+					if top.entity.Length > 0 {
+						entities = append(entities, *top.entity)
+					}
+				}
+				continue
+			}
+
+			name, attrs := parseHTMLTag(tag)
+			entity, err := htmlTagEntity(name, attrs)
+			if err != nil {
+				return "", nil, err
+			}
+
+			if name == "code" && len(stack) > 0 {
+				// <pre><code class="language-x"> is a pre block with language
+				top := stack[len(stack)-1]
+				if top.name == "pre" && top.entity != nil && top.entity.Offset == length {
+					top.entity.Language = strings.TrimPrefix(attrs["class"], "language-")
+					entity = nil
+				}
+			}
+			if entity != nil {
+				entity.Offset = length
+			}
+			stack = append(stack, htmlTag{name: name, entity: entity})
+		case '&':
+			end := strings.IndexByte(markup[i:], ';')
+			if end > 0 && end <= 10 {
+				if unescaped := html.UnescapeString(markup[i : i+end+1]); unescaped != markup[i:i+end+1] {
+					result.WriteString(unescaped)
+					length += utf16Len(unescaped)
+					i += end + 1
+					continue
+				}
+			}
+			result.WriteByte('&')
+			length++
+			i++
+		default:
+			next := strings.IndexAny(markup[i:], "<&")
+			if next < 0 {
+				next = len(markup) - i
+			}
+			result.WriteString(markup[i : i+next])
+			length += utf16Len(markup[i : i+next])
+			i += next
+		}
+	}
+
+	if len(stack) > 0 {
+		return "", nil, fmt.Errorf("unclosed tag <%s>", stack[len(stack)-1].name)
+	}
+
+	return result.String(), sortEntities(entities), nil
+}
+
+func parseHTMLTag(tag string) (string, map[string]string) {
+	tag = strings.TrimSpace(strings.TrimSuffix(tag, "/"))
+	name, rest, _ := strings.Cut(tag, " ")
+	attrs := map[string]string{}
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		end := strings.IndexAny(rest, "= ")
+		if end < 0 || rest[end] == ' ' {
+			// Attribute without value
+			if end < 0 {
+				end = len(rest)
+			}
+			attrs[strings.ToLower(rest[:end])] = ""
+			rest = rest[end:]
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(rest[:end]))
+		rest = strings.TrimSpace(rest[end+1:])
+		value := ""
+		if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+			quote := rest[0]
+			closing := strings.IndexByte(rest[1:], quote)
+			if closing < 0 {
+				closing = len(rest) - 1
+			}
+			value = rest[1 : closing+1]
+			rest = rest[min(closing+2, len(rest)):]
+		} else {
+			value, rest, _ = strings.Cut(rest, " ")
+		}
+		attrs[key] = html.UnescapeString(value)
+	}
+
+	return strings.ToLower(name), attrs
+}
+
+func htmlTagEntity(name string, attrs map[string]string) (*MessageEntity, error) {
+	entity := &MessageEntity{}
+	switch name {
+	case "b", "strong":
+		entity.Type = MESSAGE_ENTITY_BOLD
+	case "i", "em":
+		entity.Type = MESSAGE_ENTITY_ITALIC
+	case "u", "ins":
+		entity.Type = MESSAGE_ENTITY_UNDERLINE
+	case "s", "strike", "del":
+		entity.Type = MESSAGE_ENTITY_STRIKETHROUGH
+	case "tg-spoiler":
+		entity.Type = MESSAGE_ENTITY_SPOILER
+	case "span":
+		if attrs["class"] != "tg-spoiler" {
+			return nil, fmt.Errorf("unsupported span class %q", attrs["class"])
+		}
+		entity.Type = MESSAGE_ENTITY_SPOILER
+	case "code":
+		entity.Type = MESSAGE_ENTITY_CODE
+	case "pre":
+		entity.Type = MESSAGE_ENTITY_PRE
+	case "a":
+		href := attrs["href"]
+		if id, ok := strings.CutPrefix(href, "tg://user?id="); ok {
+			userID := int64(0)
+			if _, err := fmt.Sscan(id, &userID); err != nil {
+				return nil, fmt.Errorf("invalid user id in %q", href)
+			}
+			entity.Type = MESSAGE_ENTITY_TEXT_MENTION
+			entity.User = &User{ID: userID}
+		} else {
+			entity.Type = MESSAGE_ENTITY_TEXT_LINK
+			entity.URL = href
+		}
+	case "tg-emoji":
+		entity.Type = MESSAGE_ENTITY_CUSTOM_EMOJI
+		entity.CustomEmojiID = attrs["emoji-id"]
+	case "blockquote":
+		entity.Type = MESSAGE_ENTITY_BLOCKQUOTE
+		if _, ok := attrs["expandable"]; ok {
+			entity.Type = MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tag <%s>", name)
+	}
+
+	return entity, nil
+}
+
+// ParseMarkdownV2 - convert text with Telegram MarkdownV2 markup into plain text and entities.
+// It's the inverse of RenderMarkdownV2, characters ignored by Telegram (\r) are dropped.
+func ParseMarkdownV2(markup string) (string, []MessageEntity, error) {
+	result := strings.Builder{}
+	length := 0
+	entities := []MessageEntity{}
+	stack := []MessageEntity{}
+
+	write := func(text string) {
+		result.WriteString(text)
+		length += utf16Len(text)
+	}
+	open := func(entityType MessageEntityType) {
+		stack = append(stack, MessageEntity{Type: entityType, Offset: length})
+	}
+	find := func(types ...MessageEntityType) int {
+		for j := len(stack) - 1; j >= 0; j-- {
+			for _, entityType := range types {
+				if stack[j].Type == entityType {
+					return j
+				}
+			}
+		}
+		return -1
+	}
+	closeAt := func(j, end int) {
+		entity := stack[j]
+		stack = append(stack[:j], stack[j+1:]...)
+		entity.Length = end - entity.Offset
+		if entity.Length > 0 {
+			entities = append(entities, entity)
+		}
+	}
+	toggle := func(entityType MessageEntityType) {
+		if j := find(entityType); j >= 0 {
+			closeAt(j, length)
+		} else {
+			open(entityType)
+		}
+	}
+
+	lineStart := true
+	for i := 0; i < len(markup); {
+		if lineStart {
+			lineStart = false
+			quote := find(MESSAGE_ENTITY_BLOCKQUOTE, MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE)
+			switch {
+			case quote < 0 && strings.HasPrefix(markup[i:], "**>"):
+				open(MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE)
+				i += 3
+				continue
+			case markup[i] == '>':
+				if quote < 0 {
+					open(MESSAGE_ENTITY_BLOCKQUOTE)
+				}
+				i++
+				continue
+			case quote >= 0:
+				// Quotation ends with the previous line, the line break isn't quoted
+				closeAt(quote, length-1)
+			}
+		}
+
+		switch c := markup[i]; c {
+		case '\\':
+			if i+1 == len(markup) {
+				return "", nil, fmt.Errorf("unexpected end of text after \\ at byte %d", i)
+			}
+			_, size := utf8.DecodeRuneInString(markup[i+1:])
+			write(markup[i+1 : i+1+size])
+			i += 1 + size
+		case '\r':
+			i++
+		case '\n':
+			write("\n")
+			lineStart = true
+			i++
+		case '*':
+			toggle(MESSAGE_ENTITY_BOLD)
+			i++
+		case '_':
+			// __ is always greedily treated as underline
+			if strings.HasPrefix(markup[i:], "__") {
+				toggle(MESSAGE_ENTITY_UNDERLINE)
+				i += 2
+			} else {
+				toggle(MESSAGE_ENTITY_ITALIC)
+				i++
+			}
+		case '~':
+			toggle(MESSAGE_ENTITY_STRIKETHROUGH)
+			i++
+		case '|':
+			if !strings.HasPrefix(markup[i:], "||") {
+				write("|")
+				i++
+				continue
+			}
+			i += 2
+			if j := find(MESSAGE_ENTITY_SPOILER); j >= 0 {
+				closeAt(j, length)
+			} else if j := find(MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE); j >= 0 && (i == len(markup) || markup[i] == '\n') {
+				closeAt(j, length)
+			} else {
+				open(MESSAGE_ENTITY_SPOILER)
+			}
+		case '`':
+			entity := MessageEntity{Type: MESSAGE_ENTITY_CODE, Offset: length}
+			delimiter := "`"
+			if strings.HasPrefix(markup[i:], "```") {
+				entity.Type = MESSAGE_ENTITY_PRE
+				delimiter = "```"
+			}
+
+			code, next, err := parseMarkdownV2Code(markup, i+len(delimiter), delimiter)
+			if err != nil {
+				return "", nil, err
+			}
+			if entity.Type == MESSAGE_ENTITY_PRE {
+				if language, rest, found := strings.Cut(code, "\n"); found {
+					entity.Language, code = language, rest
+				}
+				code = strings.TrimSuffix(code, "\n")
+			}
+
+			write(code)
+			entity.Length = length - entity.Offset
+			if entity.Length > 0 {
+				entities = append(entities, entity)
+			}
+			i = next
+		case '[':
+			open(MESSAGE_ENTITY_TEXT_LINK)
+			i++
+		case '!':
+			if strings.HasPrefix(markup[i:], "![") {
+				open(MESSAGE_ENTITY_CUSTOM_EMOJI)
+				i += 2
+			} else {
+				write("!")
+				i++
+			}
+		case ']':
+			j := find(MESSAGE_ENTITY_TEXT_LINK, MESSAGE_ENTITY_CUSTOM_EMOJI)
+			if j < 0 {
+				return "", nil, fmt.Errorf("unexpected ] at byte %d", i)
+			}
+			url, next, err := parseMarkdownV2URL(markup, i+1)
+			if err != nil {
+				return "", nil, err
+			}
+
+			if stack[j].Type == MESSAGE_ENTITY_CUSTOM_EMOJI {
+				stack[j].CustomEmojiID = strings.TrimPrefix(url, "tg://emoji?id=")
+			} else if id, ok := strings.CutPrefix(url, "tg://user?id="); ok {
+				userID, err := strconv.ParseInt(id, 10, 64)
+				if err != nil {
+					return "", nil, fmt.Errorf("invalid user id in %q", url)
+				}
+				stack[j].Type = MESSAGE_ENTITY_TEXT_MENTION
+				stack[j].User = &User{ID: userID}
+			} else {
+				stack[j].URL = url
+			}
+			closeAt(j, length)
+			i = next
+		default:
+			next := strings.IndexAny(markup[i:], "\\\r\n*_~|`[]!")
+			if next < 0 {
+				next = len(markup) - i
+			}
+			write(markup[i : i+next])
+			i += next
+		}
+	}
+
+	if quote := find(MESSAGE_ENTITY_BLOCKQUOTE); quote >= 0 {
+		closeAt(quote, length)
+	}
+	if len(stack) > 0 {
+		return "", nil, fmt.Errorf("unclosed %s entity", stack[len(stack)-1].Type)
+	}
+
+	return result.String(), sortEntities(entities), nil
+}
+
+// parseMarkdownV2Code - unescape code until delimiter, return code and position after the delimiter
+func parseMarkdownV2Code(markup string, start int, delimiter string) (string, int, error) {
+	code := strings.Builder{}
+	for i := start; i < len(markup); {
+		switch {
+		case markup[i] == '\\' && i+1 < len(markup):
+			code.WriteByte(markup[i+1])
+			i += 2
+		case strings.HasPrefix(markup[i:], delimiter):
+			return code.String(), i + len(delimiter), nil
+		default:
+			code.WriteByte(markup[i])
+			i++
+		}
+	}
+
+	return "", 0, fmt.Errorf("unclosed %s at byte %d", delimiter, start-len(delimiter))
+}
+
+// parseMarkdownV2URL - parse "(url)" at start, return unescaped url and position after it
+func parseMarkdownV2URL(markup string, start int) (string, int, error) {
+	if start >= len(markup) || markup[start] != '(' {
+		return "", 0, fmt.Errorf("expected ( after ] at byte %d", start)
+	}
+
+	url := strings.Builder{}
+	for i := start + 1; i < len(markup); i++ {
+		switch markup[i] {
+		case '\\':
+			if i+1 < len(markup) {
+				i++
+				url.WriteByte(markup[i])
+			}
+		case ')':
+			return url.String(), i + 1, nil
+		default:
+			url.WriteByte(markup[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unclosed link url at byte %d", start)
+}
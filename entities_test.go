@@ -0,0 +1,150 @@
+package micha
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageEntityDecode(t *testing.T) {
+	message := Message{}
+	err := json.Unmarshal([]byte(`{"text":"hi 👋 bold","entities":[{"type":"bold","offset":6,"length":4}]}`), &message)
+	require.Nil(t, err)
+	require.Equal(t, []MessageEntity{{Type: MESSAGE_ENTITY_BOLD, Offset: 6, Length: 4}}, message.Entities)
+	require.Equal(t, "bold", message.Entities[0].Substring(message.Text))
+	require.Equal(t, 10, utf16Len(message.Text))
+}
+
+func TestParseEntities(t *testing.T) {
+	text := "😀 /start @john"
+	entities := []MessageEntity{
+		{Type: MESSAGE_ENTITY_MENTION, Offset: 10, Length: 5},
+		{Type: MESSAGE_ENTITY_BOT_COMMAND, Offset: 3, Length: 6},
+		{Type: MESSAGE_ENTITY_BOLD, Offset: 100, Length: 6},
+	}
+
+	parsed := ParseEntities(text, entities)
+	require.Equal(t, 3, len(parsed))
+	require.Equal(t, "/start", parsed[0].Text)
+	require.Equal(t, MESSAGE_ENTITY_BOT_COMMAND, parsed[0].Type)
+	require.Equal(t, "@john", parsed[1].Text)
+	require.Equal(t, "", parsed[2].Text)
+}
+
+func TestRenderHTML(t *testing.T) {
+	message := Message{
+		Text: "Hello <world> & 🌍 link",
+		Entities: []MessageEntity{
+			{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 13},
+			{Type: MESSAGE_ENTITY_ITALIC, Offset: 6, Length: 7},
+			{Type: MESSAGE_ENTITY_TEXT_LINK, Offset: 19, Length: 4, URL: `https://example.com/?a=1&b="2"`},
+			{Type: MESSAGE_ENTITY_URL, Offset: 0, Length: 5},
+		},
+	}
+
+	require.Equal(t,
+		`<b>Hello <i>&lt;world&gt;</i></b> &amp; 🌍 <a href="https://example.com/?a=1&amp;b=&#34;2&#34;">link</a>`,
+		message.TextHTML(),
+	)
+}
+
+func TestRenderOverlapping(t *testing.T) {
+	// bold: "abcd", italic: "cdef"
+	entities := []MessageEntity{
+		{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 4},
+		{Type: MESSAGE_ENTITY_ITALIC, Offset: 2, Length: 4},
+	}
+
+	require.Equal(t, "<b>ab<i>cd</i></b><i>ef</i>", RenderHTML("abcdef", entities))
+	require.Equal(t, "*ab_cd_\r*_ef_\r", RenderMarkdownV2("abcdef", entities))
+}
+
+func TestRenderCodeInnermost(t *testing.T) {
+	// Same span: code is nested inside bold regardless of entities order
+	entities := []MessageEntity{
+		{Type: MESSAGE_ENTITY_CODE, Offset: 0, Length: 6},
+		{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 6},
+	}
+	require.Equal(t, "<b><code>code x</code></b>", RenderHTML("code x", entities))
+	require.Equal(t, "*`code x`*", RenderMarkdownV2("code x", entities))
+
+	// Entities inside code are dropped
+	entities = []MessageEntity{
+		{Type: MESSAGE_ENTITY_PRE, Offset: 0, Length: 6},
+		{Type: MESSAGE_ENTITY_ITALIC, Offset: 2, Length: 2},
+	}
+	require.Equal(t, "<pre>abcdef</pre>", RenderHTML("abcdef", entities))
+	require.Equal(t, "```\nabcdef\n```", RenderMarkdownV2("abcdef", entities))
+}
+
+func TestRenderMarkdownV2(t *testing.T) {
+	message := Message{
+		Caption: "Price: 1.5$ code_x\nquote\nline (2)",
+		CaptionEntities: []MessageEntity{
+			{Type: MESSAGE_ENTITY_CODE, Offset: 12, Length: 6},
+			{Type: MESSAGE_ENTITY_BLOCKQUOTE, Offset: 19, Length: 14},
+			{Type: MESSAGE_ENTITY_TEXT_MENTION, Offset: 0, Length: 5, User: &User{ID: 42}},
+		},
+	}
+
+	require.Equal(t,
+		"[Price](tg://user?id=42): 1\\.5$ `code_x`\n>quote\n>line \\(2\\)",
+		message.CaptionMarkdownV2(),
+	)
+
+	require.Equal(t,
+		"```go\nfmt.Println(\"\\`\")\n```",
+		RenderMarkdownV2("fmt.Println(\"`\")", []MessageEntity{{Type: MESSAGE_ENTITY_PRE, Offset: 0, Length: 16, Language: "go"}}),
+	)
+	require.Equal(t,
+		`<pre><code class="language-go">x &lt; y</code></pre>`,
+		RenderHTML("x < y", []MessageEntity{{Type: MESSAGE_ENTITY_PRE, Offset: 0, Length: 5, Language: "go"}}),
+	)
+}
+
+func TestParseMarkdownV2(t *testing.T) {
+	for _, c := range []struct {
+		text     string
+		entities []MessageEntity
+	}{
+		{"plain 1.5! (x) [y] a_b", nil},
+		{"abcdef", []MessageEntity{
+			{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 6},
+			{Type: MESSAGE_ENTITY_ITALIC, Offset: 2, Length: 2},
+		}},
+		{"ab", []MessageEntity{
+			{Type: MESSAGE_ENTITY_UNDERLINE, Offset: 0, Length: 2},
+			{Type: MESSAGE_ENTITY_ITALIC, Offset: 0, Length: 1},
+			{Type: MESSAGE_ENTITY_STRIKETHROUGH, Offset: 1, Length: 1},
+		}},
+		{"Price: 1.5$ code_x\nquote\nline (2)\nend", []MessageEntity{
+			{Type: MESSAGE_ENTITY_TEXT_MENTION, Offset: 0, Length: 5, User: &User{ID: 42}},
+			{Type: MESSAGE_ENTITY_CODE, Offset: 12, Length: 6},
+			{Type: MESSAGE_ENTITY_BLOCKQUOTE, Offset: 19, Length: 14},
+		}},
+		{"> bold secret\nline1\nline2\nx := `1`👍 link", []MessageEntity{
+			{Type: MESSAGE_ENTITY_BOLD, Offset: 2, Length: 11},
+			{Type: MESSAGE_ENTITY_SPOILER, Offset: 7, Length: 6},
+			{Type: MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE, Offset: 14, Length: 11},
+			{Type: MESSAGE_ENTITY_PRE, Offset: 26, Length: 8, Language: "go"},
+			{Type: MESSAGE_ENTITY_CUSTOM_EMOJI, Offset: 34, Length: 2, CustomEmojiID: "123"},
+			{Type: MESSAGE_ENTITY_TEXT_LINK, Offset: 37, Length: 4, URL: "https://example.com/a_(b)"},
+		}},
+	} {
+		markup := RenderMarkdownV2(c.text, c.entities)
+		text, entities, err := ParseMarkdownV2(markup)
+		require.Nil(t, err, markup)
+		require.Equal(t, c.text, text, markup)
+		if c.entities == nil {
+			require.Empty(t, entities, markup)
+		} else {
+			require.Equal(t, c.entities, entities, markup)
+		}
+	}
+
+	for _, markup := range []string{"*bold", "`code", "```pre", "[link]", "[link](url", "text]", "\\"} {
+		_, _, err := ParseMarkdownV2(markup)
+		require.NotNil(t, err, markup)
+	}
+}
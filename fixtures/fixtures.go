@@ -0,0 +1,384 @@
+// Package fixtures provides builders of updates for bot tests.
+// Built values are the same as decoded from the real API responses,
+// entity offsets are counted in UTF-16 code units.
+//
+//	user := fixtures.User(42, "Alice")
+//	update := fixtures.PrivateMessage(user, "").WithCommand("/start", "ref").Update()
+package fixtures
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf16"
+
+	"github.com/onrik/micha"
+)
+
+var (
+	lastUpdateID   atomic.Uint64
+	lastMessageID  atomic.Int64
+	lastCallbackID atomic.Int64
+	lastInlineID   atomic.Int64
+)
+
+// NextUpdateID - return next update id, ids are unique within the test binary
+func NextUpdateID() uint64 {
+	return lastUpdateID.Add(1)
+}
+
+// NextMessageID - return next message id
+func NextMessageID() int64 {
+	return lastMessageID.Add(1)
+}
+
+func chatID(id int64) micha.ChatID {
+	return micha.ChatIDFromInt(id)
+}
+
+func utf16Len(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// User - create user
+func User(id int64, firstName string) micha.User {
+	return micha.User{ID: id, FirstName: firstName}
+}
+
+// Bot - create bot user
+func Bot(id int64, username string) micha.User {
+	return micha.User{ID: id, IsBot: true, FirstName: username, Username: username}
+}
+
+// PrivateChat - create private chat with user
+func PrivateChat(user micha.User) micha.Chat {
+	return micha.Chat{
+		ID:        chatID(user.ID),
+		Type:      micha.CHAT_TYPE_PRIVATE,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}
+}
+
+// Group - create group chat, id is negative for groups
+func Group(id int64, title string) micha.Chat {
+	return micha.Chat{ID: chatID(id), Type: micha.CHAT_TYPE_GROUP, Title: title}
+}
+
+// Supergroup - create supergroup chat, id is negative with -100 prefix for supergroups
+func Supergroup(id int64, title string) micha.Chat {
+	return micha.Chat{ID: chatID(id), Type: micha.CHAT_TYPE_SUPERGROUP, Title: title}
+}
+
+// Channel - create channel chat
+func Channel(id int64, title string) micha.Chat {
+	return micha.Chat{ID: chatID(id), Type: micha.CHAT_TYPE_CHANNEL, Title: title}
+}
+
+// MessageBuilder - builder of message and message updates
+type MessageBuilder struct {
+	message micha.Message
+}
+
+// PrivateMessage - create text message from user in private chat with the bot
+func PrivateMessage(from micha.User, text string) *MessageBuilder {
+	return ChatMessage(PrivateChat(from), from, text)
+}
+
+// ChatMessage - create text message from user in chat.
+// Bot command at the beginning of the text gets entity like in the real API.
+func ChatMessage(chat micha.Chat, from micha.User, text string) *MessageBuilder {
+	b := &MessageBuilder{
+		message: micha.Message{
+			MessageID: NextMessageID(),
+			From:      &from,
+			Date:      micha.UnixTimeFromTime(time.Now()),
+			Chat:      chat,
+			Text:      text,
+		},
+	}
+
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		b.addEntity(micha.MessageEntity{Type: micha.MESSAGE_ENTITY_BOT_COMMAND, Length: utf16Len(command)})
+	}
+
+	return b
+}
+
+// ChannelPost - create text post in channel, it has no sender user and the channel as sender chat
+func ChannelPost(channel micha.Chat, text string) *MessageBuilder {
+	b := ChatMessage(channel, micha.User{}, text)
+	b.message.From = nil
+	b.message.SenderChat = &channel
+	return b
+}
+
+func (b *MessageBuilder) addEntity(entity micha.MessageEntity) {
+	if b.message.Text == "" && b.message.Caption != "" {
+		b.message.CaptionEntities = append(b.message.CaptionEntities, entity)
+	} else {
+		b.message.Entities = append(b.message.Entities, entity)
+	}
+}
+
+// WithID - set message id
+func (b *MessageBuilder) WithID(id int64) *MessageBuilder {
+	b.message.MessageID = id
+	return b
+}
+
+// At - set message date
+func (b *MessageBuilder) At(t time.Time) *MessageBuilder {
+	b.message.Date = micha.UnixTimeFromTime(t)
+	return b
+}
+
+// WithCommand - replace text with bot command and optional payload ("/start payload").
+// Command may contain bot username ("/start@my_bot").
+func (b *MessageBuilder) WithCommand(command, payload string) *MessageBuilder {
+	if !strings.HasPrefix(command, "/") {
+		command = "/" + command
+	}
+
+	b.message.Text = command
+	if payload != "" {
+		b.message.Text += " " + payload
+	}
+	b.message.Entities = []micha.MessageEntity{{Type: micha.MESSAGE_ENTITY_BOT_COMMAND, Length: utf16Len(command)}}
+
+	return b
+}
+
+// WithText - replace text and entities with formatted text
+func (b *MessageBuilder) WithText(text *micha.Text) *MessageBuilder {
+	b.message.Text = text.String()
+	b.message.Entities = text.Entities()
+	return b
+}
+
+// WithEntity - add entity for the first occurrence of substring in text (or caption).
+// Panics if substring is not found.
+func (b *MessageBuilder) WithEntity(entityType micha.MessageEntityType, substring string) *MessageBuilder {
+	return b.withEntity(micha.MessageEntity{Type: entityType}, substring)
+}
+
+// WithTextLink - add text link entity for the first occurrence of substring
+func (b *MessageBuilder) WithTextLink(substring, url string) *MessageBuilder {
+	return b.withEntity(micha.MessageEntity{Type: micha.MESSAGE_ENTITY_TEXT_LINK, URL: url}, substring)
+}
+
+// WithMention - add text mention entity of user without username for the first occurrence of substring
+func (b *MessageBuilder) WithMention(substring string, user micha.User) *MessageBuilder {
+	return b.withEntity(micha.MessageEntity{Type: micha.MESSAGE_ENTITY_TEXT_MENTION, User: &user}, substring)
+}
+
+func (b *MessageBuilder) withEntity(entity micha.MessageEntity, substring string) *MessageBuilder {
+	text := b.message.Text
+	if text == "" {
+		text = b.message.Caption
+	}
+
+	index := strings.Index(text, substring)
+	if index < 0 || substring == "" {
+		panic("fixtures: " + strconv.Quote(substring) + " not found in message text")
+	}
+
+	entity.Offset = utf16Len(text[:index])
+	entity.Length = utf16Len(substring)
+	b.addEntity(entity)
+
+	return b
+}
+
+// WithPhoto - make photo message with caption
+func (b *MessageBuilder) WithPhoto(fileID, caption string) *MessageBuilder {
+	b.message.Text = ""
+	b.message.Entities = nil
+	b.message.Caption = caption
+	b.message.Photo = []micha.PhotoSize{{FileID: fileID, Width: 90, Height: 90}}
+	return b
+}
+
+// WithDocument - make document message with caption
+func (b *MessageBuilder) WithDocument(document micha.Document, caption string) *MessageBuilder {
+	b.message.Text = ""
+	b.message.Entities = nil
+	b.message.Caption = caption
+	b.message.Document = &document
+	return b
+}
+
+// Anonymous - send message on behalf of chat like anonymous group administrators do
+func (b *MessageBuilder) Anonymous(senderChat micha.Chat) *MessageBuilder {
+	b.message.From = &micha.User{ID: 1087968824, IsBot: true, FirstName: "Group", Username: "GroupAnonymousBot"}
+	b.message.SenderChat = &senderChat
+	return b
+}
+
+// ReplyTo - make message a reply
+func (b *MessageBuilder) ReplyTo(message micha.Message) *MessageBuilder {
+	b.message.ReplyToMessage = &message
+	return b
+}
+
+// InTopic - put message to forum topic
+func (b *MessageBuilder) InTopic(threadID int64) *MessageBuilder {
+	b.message.MessageThreadID = threadID
+	b.message.IsTopicMessage = true
+	return b
+}
+
+// WithReplyMarkup - attach inline keyboard
+func (b *MessageBuilder) WithReplyMarkup(markup micha.InlineKeyboardMarkup) *MessageBuilder {
+	b.message.ReplyMarkup = markup
+	return b
+}
+
+// Edited - mark message as edited, Update returns edited_message update
+func (b *MessageBuilder) Edited(at time.Time) *MessageBuilder {
+	b.message.EditDate = micha.UnixTimeFromTime(at)
+	return b
+}
+
+// Message - return message
+func (b *MessageBuilder) Message() micha.Message {
+	return b.message
+}
+
+// Update - return message update, channel_post for channels and edited_* for edited messages
+func (b *MessageBuilder) Update() micha.Update {
+	message := b.message
+	update := micha.Update{UpdateID: NextUpdateID()}
+
+	channel := message.Chat.Type == micha.CHAT_TYPE_CHANNEL
+	edited := message.EditDate > 0
+	switch {
+	case channel && edited:
+		update.EditedChannelPost = &message
+	case channel:
+		update.ChannelPost = &message
+	case edited:
+		update.EditedMessage = &message
+	default:
+		update.Message = &message
+	}
+
+	return update
+}
+
+// CallbackQueryBuilder - builder of callback query updates
+type CallbackQueryBuilder struct {
+	query micha.CallbackQuery
+}
+
+// CallbackQuery - create callback query from user pressed button with data on the message
+func CallbackQuery(from micha.User, message micha.Message, data string) *CallbackQueryBuilder {
+	return &CallbackQueryBuilder{
+		query: micha.CallbackQuery{
+			ID:           strconv.FormatInt(lastCallbackID.Add(1), 10),
+			From:         from,
+			Message:      &message,
+			ChatInstance: string(message.Chat.ID),
+			Data:         data,
+		},
+	}
+}
+
+// InlineCallbackQuery - create callback query from button of inline message
+func InlineCallbackQuery(from micha.User, inlineMessageID, data string) *CallbackQueryBuilder {
+	return &CallbackQueryBuilder{
+		query: micha.CallbackQuery{
+			ID:              strconv.FormatInt(lastCallbackID.Add(1), 10),
+			From:            from,
+			InlineMessageID: inlineMessageID,
+			ChatInstance:    inlineMessageID,
+			Data:            data,
+		},
+	}
+}
+
+// WithID - set callback query id
+func (b *CallbackQueryBuilder) WithID(id string) *CallbackQueryBuilder {
+	b.query.ID = id
+	return b
+}
+
+// WithGame - replace data with game short name
+func (b *CallbackQueryBuilder) WithGame(gameShortName string) *CallbackQueryBuilder {
+	b.query.Data = ""
+	b.query.GameShortName = gameShortName
+	return b
+}
+
+// CallbackQuery - return callback query
+func (b *CallbackQueryBuilder) CallbackQuery() micha.CallbackQuery {
+	return b.query
+}
+
+// Update - return callback_query update
+func (b *CallbackQueryBuilder) Update() micha.Update {
+	query := b.query
+	return micha.Update{UpdateID: NextUpdateID(), CallbackQuery: &query}
+}
+
+// InlineQueryBuilder - builder of inline query updates
+type InlineQueryBuilder struct {
+	query micha.InlineQuery
+}
+
+// InlineQuery - create inline query from user
+func InlineQuery(from micha.User, query string) *InlineQueryBuilder {
+	return &InlineQueryBuilder{
+		query: micha.InlineQuery{
+			ID:    strconv.FormatInt(lastInlineID.Add(1), 10),
+			From:  from,
+			Query: query,
+		},
+	}
+}
+
+// WithID - set inline query id
+func (b *InlineQueryBuilder) WithID(id string) *InlineQueryBuilder {
+	b.query.ID = id
+	return b
+}
+
+// WithOffset - set offset of requested page
+func (b *InlineQueryBuilder) WithOffset(offset string) *InlineQueryBuilder {
+	b.query.Offset = offset
+	return b
+}
+
+// WithLocation - set user location
+func (b *InlineQueryBuilder) WithLocation(latitude, longitude float64) *InlineQueryBuilder {
+	b.query.Location = &micha.Location{Latitude: latitude, Longitude: longitude}
+	return b
+}
+
+// InlineQuery - return inline query
+func (b *InlineQueryBuilder) InlineQuery() micha.InlineQuery {
+	return b.query
+}
+
+// Update - return inline_query update
+func (b *InlineQueryBuilder) Update() micha.Update {
+	query := b.query
+	return micha.Update{UpdateID: NextUpdateID(), InlineQuery: &query}
+}
+
+// ChosenInlineResult - create chosen_inline_result update for result of inline query
+func ChosenInlineResult(query micha.InlineQuery, resultID, inlineMessageID string) micha.Update {
+	return micha.Update{
+		UpdateID: NextUpdateID(),
+		ChosenInlineResult: &micha.ChosenInlineResult{
+			ResultID:        resultID,
+			From:            query.From,
+			Location:        query.Location,
+			InlineMessageID: inlineMessageID,
+			Query:           query.Query,
+		},
+	}
+}
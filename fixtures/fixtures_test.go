@@ -0,0 +1,182 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/onrik/micha"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeUpdate(t *testing.T, data string) micha.Update {
+	update := micha.Update{}
+	require.Nil(t, json.Unmarshal([]byte(data), &update))
+
+	return update
+}
+
+func TestPrivateMessage(t *testing.T) {
+	user := User(42, "Alice")
+	date := time.Unix(1700000000, 0)
+
+	update := PrivateMessage(user, "").WithCommand("start", "ref").WithID(7).At(date).Update()
+	update.UpdateID = 1
+
+	expected := decodeUpdate(t, `{
+		"update_id": 1,
+		"message": {
+			"message_id": 7,
+			"from": {"id": 42, "is_bot": false, "first_name": "Alice"},
+			"chat": {"id": 42, "type": "private", "first_name": "Alice"},
+			"date": 1700000000,
+			"text": "/start ref",
+			"entities": [{"type": "bot_command", "offset": 0, "length": 6}]
+		}
+	}`)
+	require.Equal(t, expected, update)
+
+	// Command in text is detected automatically
+	message := PrivateMessage(user, "/help@test_bot me").Message()
+	require.Equal(t, []micha.MessageEntity{{Type: micha.MESSAGE_ENTITY_BOT_COMMAND, Length: 14}}, message.Entities)
+}
+
+func TestMessageEntities(t *testing.T) {
+	user := User(42, "Alice")
+
+	message := ChatMessage(Supergroup(-1001, "Group"), user, "👋 Hi #go, see docs").
+		WithEntity(micha.MESSAGE_ENTITY_HASHTAG, "#go").
+		WithTextLink("docs", "https://example.com").
+		WithMention("Hi", user).
+		Message()
+	require.Equal(t, micha.ChatID("-1001"), message.Chat.ID)
+	require.Equal(t, []micha.MessageEntity{
+		{Type: micha.MESSAGE_ENTITY_HASHTAG, Offset: 6, Length: 3},
+		{Type: micha.MESSAGE_ENTITY_TEXT_LINK, Offset: 15, Length: 4, URL: "https://example.com"},
+		{Type: micha.MESSAGE_ENTITY_TEXT_MENTION, Offset: 3, Length: 2, User: &user},
+	}, message.Entities)
+	require.Equal(t, []string{"#go", "docs", "Hi"}, []string{
+		message.Entities[0].Substring(message.Text),
+		message.Entities[1].Substring(message.Text),
+		message.Entities[2].Substring(message.Text),
+	})
+
+	photo := PrivateMessage(user, "").WithPhoto("photo-id", "Look 👀 here").WithEntity(micha.MESSAGE_ENTITY_BOLD, "here").Message()
+	require.Empty(t, photo.Entities)
+	require.Equal(t, []micha.MessageEntity{{Type: micha.MESSAGE_ENTITY_BOLD, Offset: 8, Length: 4}}, photo.CaptionEntities)
+
+	formatted := PrivateMessage(user, "").WithText(micha.NewText().Plain("a ").Bold("b")).Message()
+	require.Equal(t, "a b", formatted.Text)
+	require.Equal(t, []micha.MessageEntity{{Type: micha.MESSAGE_ENTITY_BOLD, Offset: 2, Length: 1}}, formatted.Entities)
+
+	require.Panics(t, func() {
+		PrivateMessage(user, "text").WithEntity(micha.MESSAGE_ENTITY_BOLD, "missing")
+	})
+}
+
+func TestMessageUpdateKinds(t *testing.T) {
+	user := User(42, "Alice")
+
+	require.NotNil(t, PrivateMessage(user, "hi").Update().Message)
+	require.NotNil(t, PrivateMessage(user, "hi").Edited(time.Now()).Update().EditedMessage)
+	require.NotNil(t, ChatMessage(Channel(-1002, "News"), user, "post").Update().ChannelPost)
+	require.NotNil(t, ChatMessage(Channel(-1002, "News"), user, "post").Edited(time.Now()).Update().EditedChannelPost)
+
+	post := ChannelPost(Channel(-1002, "News"), "post").Update()
+	require.Equal(t, micha.UPDATE_KIND_CHANNEL_POST, post.Kind())
+	require.Nil(t, post.EffectiveUser())
+	require.Equal(t, micha.ChatIDFromInt(-1002), post.EffectiveSender().Chat.ID)
+
+	anonymous := ChatMessage(Supergroup(-1003, "Group"), user, "hi").Anonymous(Supergroup(-1003, "Group")).Update()
+	require.Equal(t, micha.ChatIDFromInt(-1003), anonymous.EffectiveSender().Chat.ID)
+	require.True(t, anonymous.EffectiveUser().IsBot)
+
+	first := PrivateMessage(user, "a").Update()
+	second := PrivateMessage(user, "b").Update()
+	require.Greater(t, second.UpdateID, first.UpdateID)
+	require.Greater(t, second.Message.MessageID, first.Message.MessageID)
+}
+
+func TestCallbackQuery(t *testing.T) {
+	user := User(42, "Alice")
+	message := PrivateMessage(Bot(1, "test_bot"), "Choose").WithID(3).At(time.Unix(1700000000, 0)).Message()
+	message.Chat = PrivateChat(user)
+
+	update := CallbackQuery(user, message, "yes").WithID("q1").Update()
+	update.UpdateID = 2
+
+	expected := decodeUpdate(t, `{
+		"update_id": 2,
+		"callback_query": {
+			"id": "q1",
+			"from": {"id": 42, "is_bot": false, "first_name": "Alice"},
+			"message": {
+				"message_id": 3,
+				"from": {"id": 1, "is_bot": true, "first_name": "test_bot", "username": "test_bot"},
+				"chat": {"id": 42, "type": "private", "first_name": "Alice"},
+				"date": 1700000000,
+				"text": "Choose"
+			},
+			"chat_instance": "42",
+			"data": "yes"
+		}
+	}`)
+	require.Equal(t, expected, update)
+
+	inline := InlineCallbackQuery(user, "inline-1", "").WithGame("snake").CallbackQuery()
+	require.Equal(t, "inline-1", inline.InlineMessageID)
+	require.Equal(t, "snake", inline.GameShortName)
+	require.Nil(t, inline.Message)
+}
+
+func TestInlineQuery(t *testing.T) {
+	user := User(42, "Alice")
+
+	update := InlineQuery(user, "cats").WithID("iq").WithOffset("10").WithLocation(1.5, 2.5).Update()
+	update.UpdateID = 3
+
+	expected := decodeUpdate(t, `{
+		"update_id": 3,
+		"inline_query": {
+			"id": "iq",
+			"from": {"id": 42, "is_bot": false, "first_name": "Alice"},
+			"location": {"latitude": 1.5, "longitude": 2.5},
+			"query": "cats",
+			"offset": "10"
+		}
+	}`)
+	require.Equal(t, expected, update)
+
+	chosen := ChosenInlineResult(*update.InlineQuery, "r1", "im1").ChosenInlineResult
+	require.Equal(t, &micha.ChosenInlineResult{
+		ResultID:        "r1",
+		From:            user,
+		Location:        &micha.Location{Latitude: 1.5, Longitude: 2.5},
+		InlineMessageID: "im1",
+		Query:           "cats",
+	}, chosen)
+}
+
+func TestAnonymousSessionKeys(t *testing.T) {
+	group := Supergroup(-1001, "Group")
+	other := Supergroup(-1002, "Other")
+
+	first := ChatMessage(group, User(1, "Alice"), "hi").Anonymous(group).Update()
+	second := ChatMessage(other, User(2, "Bob"), "hi").Anonymous(other).Update()
+
+	// Anonymous administrators of different groups don't share the placeholder user session
+	key, ok := micha.SessionKeyUser(first)
+	require.True(t, ok)
+	require.Equal(t, "sender_chat:-1001", key)
+	key, ok = micha.SessionKeyUser(second)
+	require.True(t, ok)
+	require.Equal(t, "sender_chat:-1002", key)
+
+	key, ok = micha.SessionKeyUserInChat(first)
+	require.True(t, ok)
+	require.Equal(t, "chat:-1001:sender_chat:-1001", key)
+
+	key, ok = micha.SessionKeyUser(ChatMessage(group, User(1, "Alice"), "hi").Update())
+	require.True(t, ok)
+	require.Equal(t, "user:1", key)
+}
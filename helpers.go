@@ -2,31 +2,216 @@ package micha
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 )
 
-// Convert struct to url values map
-// TODO: temp implementation
-func structToValues(obj interface{}) (url.Values, error) {
-	data, err := json.Marshal(obj)
+// structToValues - encode struct to form values the same way the JSON path sends it.
+// Field names and omitempty are taken from json tags, embedded structs are flattened.
+// Strings and numbers are formatted natively, objects and arrays are JSON encoded per field.
+func structToValues(codec JSONCodec, obj interface{}) (url.Values, error) {
+	values := url.Values{}
+
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return values, nil
+	}
+
+	if v.Type().Implements(jsonMarshalerType) {
+		return marshalerToValues(codec, obj)
+	}
+
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't encode %s to form values", v.Type())
+	}
+
+	if err := encodeStructValues(codec, v, values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// marshalerToValues - encode value with custom MarshalJSON which must produce an object
+func marshalerToValues(codec JSONCodec, obj interface{}) (url.Values, error) {
+	data, err := codec.Marshal(obj)
 	if err != nil {
 		return nil, err
 	}
 
-	rawMap := map[string]json.RawMessage{}
-	if err := json.Unmarshal(data, &rawMap); err != nil {
+	fields := map[string]json.RawMessage{}
+	if err := codec.Unmarshal(data, &fields); err != nil {
 		return nil, err
 	}
 
 	values := url.Values{}
-	for key := range rawMap {
-		values.Set(key, strings.Trim(string(rawMap[key]), `/"`))
+	for key, raw := range fields {
+		values.Set(key, rawToValue(codec, raw))
 	}
 
 	return values, nil
 }
 
+func encodeStructValues(codec JSONCodec, v reflect.Value, values url.Values) error {
+	t := v.Type()
+	embedded := []reflect.Value{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		value := v.Field(i)
+
+		if field.Anonymous && name == "" {
+			// Fields of embedded struct are encoded after own fields, so own fields win on conflict
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				embedded = append(embedded, value)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if values.Has(name) {
+			continue
+		}
+		if isEmptyValue(value) && (slices.Contains(strings.Split(options, ","), "omitempty") || isNilValue(value)) {
+			continue
+		}
+
+		s, err := formValue(codec, value)
+		if err != nil {
+			return fmt.Errorf("encode %s error: %w", name, err)
+		}
+		values.Set(name, s)
+	}
+
+	for _, value := range embedded {
+		embeddedValues := url.Values{}
+		if err := encodeStructValues(codec, value, embeddedValues); err != nil {
+			return err
+		}
+		for name := range embeddedValues {
+			if !values.Has(name) {
+				values[name] = embeddedValues[name]
+			}
+		}
+	}
+
+	return nil
+}
+
+// formValue - format scalar natively, other values are JSON encoded
+func formValue(codec JSONCodec, v reflect.Value) (string, error) {
+	if !v.Type().Implements(jsonMarshalerType) {
+		switch v.Kind() {
+		case reflect.String:
+			return v.String(), nil
+		case reflect.Bool:
+			return strconv.FormatBool(v.Bool()), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(v.Int(), 10), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(v.Uint(), 10), nil
+		case reflect.Float32, reflect.Float64:
+			return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+		case reflect.Pointer:
+			return formValue(codec, v.Elem())
+		}
+	}
+
+	data, err := codec.Marshal(v.Interface())
+	if err != nil {
+		return "", err
+	}
+
+	return rawToValue(codec, data), nil
+}
+
+// rawToValue - unquote JSON string, other JSON values are kept as is
+func rawToValue(codec JSONCodec, raw []byte) string {
+	s := ""
+	if len(raw) > 0 && raw[0] == '"' && codec.Unmarshal(raw, &s) == nil {
+		return s
+	}
+
+	return string(raw)
+}
+
+// isEmptyValue - same rules as omitempty of encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// isNilValue - nil values are sent as null in JSON, form has no null so they are skipped
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// SplitMessageIDs - sort message ids, remove duplicates and split them into chunks of at most size ids.
+// Non positive size means MaxBatchMessages. The passed slice is not modified.
+func SplitMessageIDs(messageIDs []int64, size int) [][]int64 {
+	if size <= 0 {
+		size = MaxBatchMessages
+	}
+
+	// Batch methods require strictly increasing ids
+	messageIDs = slices.Clone(messageIDs)
+	slices.Sort(messageIDs)
+	messageIDs = slices.Compact(messageIDs)
+
+	chunks := make([][]int64, 0, (len(messageIDs)+size-1)/size)
+	for len(messageIDs) > size {
+		chunks = append(chunks, messageIDs[:size:size])
+		messageIDs = messageIDs[size:]
+	}
+	if len(messageIDs) > 0 {
+		chunks = append(chunks, messageIDs)
+	}
+
+	return chunks
+}
+
 type sendMessageParams struct {
 	SendMessageOptions
 	ChatID ChatID `json:"chat_id"`
@@ -67,6 +252,9 @@ func newSendAudioParams(chatID ChatID, audio string, options *SendAudioOptions)
 	if options != nil {
 		params.SendAudioOptions = *options
 	}
+	if params.Thumbnail == "" {
+		params.Thumbnail = params.Thumb
+	}
 
 	return params
 }
@@ -86,6 +274,9 @@ func newSendDocumentParams(chatID ChatID, document string, options *SendDocument
 	if options != nil {
 		params.SendDocumentOptions = *options
 	}
+	if params.Thumbnail == "" {
+		params.Thumbnail = params.Thumb
+	}
 
 	return params
 }
@@ -124,6 +315,31 @@ func newSendVideoParams(chatID ChatID, video string, options *SendVideoOptions)
 	if options != nil {
 		params.SendVideoOptions = *options
 	}
+	if params.Thumbnail == "" {
+		params.Thumbnail = params.Thumb
+	}
+
+	return params
+}
+
+type sendAnimationParams struct {
+	ChatID    ChatID `json:"chat_id"`
+	Animation string `json:"animation,omitempty"`
+	SendAnimationOptions
+}
+
+func newSendAnimationParams(chatID ChatID, animation string, options *SendAnimationOptions) *sendAnimationParams {
+	params := &sendAnimationParams{
+		ChatID:    chatID,
+		Animation: animation,
+	}
+
+	if options != nil {
+		params.SendAnimationOptions = *options
+	}
+	if params.Thumbnail == "" {
+		params.Thumbnail = params.Thumb
+	}
 
 	return params
 }
@@ -162,6 +378,9 @@ func newSendVideoNoteParams(chatID ChatID, videoNote string, options *SendVideoN
 	if options != nil {
 		params.SendVideoNoteOptions = *options
 	}
+	if params.Thumbnail == "" {
+		params.Thumbnail = params.Thumb
+	}
 
 	return params
 }
@@ -187,6 +406,22 @@ func newSendLocationParams(chatID ChatID, latitude, longitude float64, options *
 	return params
 }
 
+type editMessageLiveLocationParams struct {
+	ChatID          ChatID  `json:"chat_id,omitempty"`
+	MessageID       int64   `json:"message_id,omitempty"`
+	InlineMessageID string  `json:"inline_message_id,omitempty"`
+	Latitude        float64 `json:"latitude"`
+	Longitude       float64 `json:"longitude"`
+	EditMessageLiveLocationOptions
+}
+
+type stopMessageLiveLocationParams struct {
+	ChatID          ChatID `json:"chat_id,omitempty"`
+	MessageID       int64  `json:"message_id,omitempty"`
+	InlineMessageID string `json:"inline_message_id,omitempty"`
+	StopMessageLiveLocationOptions
+}
+
 type sendVenueParams struct {
 	ChatID    ChatID  `json:"chat_id"`
 	Latitude  float64 `json:"latitude,omitempty"`
@@ -235,6 +470,80 @@ func newSendContactParams(chatID ChatID, phoneNumber, firstName, lastName string
 	return params
 }
 
+type sendDiceParams struct {
+	ChatID ChatID    `json:"chat_id"`
+	Emoji  DiceEmoji `json:"emoji,omitempty"`
+	SendDiceOptions
+}
+
+type sendPaidMediaParams struct {
+	ChatID    ChatID           `json:"chat_id"`
+	StarCount int              `json:"star_count"`
+	Media     []InputPaidMedia `json:"media"`
+	SendPaidMediaOptions
+}
+
+func newSendPaidMediaParams(chatID ChatID, starCount int, media []InputPaidMedia, options *SendPaidMediaOptions) *sendPaidMediaParams {
+	params := &sendPaidMediaParams{
+		ChatID:    chatID,
+		StarCount: starCount,
+		Media:     media,
+	}
+	if options != nil {
+		params.SendPaidMediaOptions = *options
+	}
+
+	return params
+}
+
+type sendPollParams struct {
+	ChatID   ChatID            `json:"chat_id"`
+	Question string            `json:"question"`
+	Options  []InputPollOption `json:"options"`
+	SendPollOptions
+}
+
+func newSendPollParams(chatID ChatID, question string, pollOptions []InputPollOption, options *SendPollOptions) *sendPollParams {
+	params := &sendPollParams{
+		ChatID:   chatID,
+		Question: question,
+		Options:  pollOptions,
+	}
+
+	if options != nil {
+		params.SendPollOptions = *options
+	}
+
+	return params
+}
+
+type stopPollParams struct {
+	ChatID      ChatID      `json:"chat_id"`
+	MessageID   int64       `json:"message_id"`
+	ReplyMarkup ReplyMarkup `json:"reply_markup,omitempty"`
+}
+
+type copyMessageParams struct {
+	ChatID     ChatID `json:"chat_id"`
+	FromChatID ChatID `json:"from_chat_id"`
+	MessageID  int64  `json:"message_id"`
+	CopyMessageOptions
+}
+
+type forwardMessagesParams struct {
+	ChatID     ChatID  `json:"chat_id"`
+	FromChatID ChatID  `json:"from_chat_id"`
+	MessageIDs []int64 `json:"message_ids"`
+	ForwardMessagesOptions
+}
+
+type copyMessagesParams struct {
+	ChatID     ChatID  `json:"chat_id"`
+	FromChatID ChatID  `json:"from_chat_id"`
+	MessageIDs []int64 `json:"message_ids"`
+	CopyMessagesOptions
+}
+
 type sendGameParams struct {
 	ChatID        ChatID `json:"chat_id"`
 	GameShortName string `json:"game_short_name"`
@@ -247,6 +556,13 @@ type setGameScoreParams struct {
 	SetGameScoreOptions
 }
 
+type setMessageReactionParams struct {
+	ChatID    ChatID         `json:"chat_id"`
+	MessageID int64          `json:"message_id"`
+	Reaction  []ReactionType `json:"reaction"`
+	IsBig     bool           `json:"is_big,omitempty"`
+}
+
 type answerCallbackQueryParams struct {
 	CallbackQueryID string `json:"callback_query_id"`
 	AnswerCallbackQueryOptions
@@ -286,8 +602,27 @@ type editMessageReplyMarkupParams struct {
 	ReplyMarkup     ReplyMarkup `json:"reply_markup,omitempty"`
 }
 
+type editMessageMediaParams struct {
+	ChatID          ChatID      `json:"chat_id,omitempty"`
+	MessageID       int64       `json:"message_id,omitempty"`
+	InlineMessageID string      `json:"inline_message_id,omitempty"`
+	Media           InputMedia  `json:"media"`
+	ReplyMarkup     ReplyMarkup `json:"reply_markup,omitempty"`
+}
+
 type answerInlineQueryParams struct {
 	InlineQueryID string             `json:"inline_query_id"`
 	Results       InlineQueryResults `json:"results"`
 	AnswerInlineQueryOptions
 }
+
+type answerWebAppQueryParams struct {
+	WebAppQueryID string            `json:"web_app_query_id"`
+	Result        InlineQueryResult `json:"result"`
+}
+
+type savePreparedInlineMessageParams struct {
+	UserID int64             `json:"user_id"`
+	Result InlineQueryResult `json:"result"`
+	SavePreparedInlineMessageOptions
+}
@@ -1,10 +1,13 @@
 package micha
 
 import (
+	"encoding/json"
 	"errors"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testStruct struct{}
@@ -14,9 +17,117 @@ func (s testStruct) MarshalJSON() ([]byte, error) {
 }
 
 func TestStructToValues(t *testing.T) {
-	_, err := structToValues(testStruct{})
+	_, err := structToValues(stdJSONCodec{}, testStruct{})
 	assert.NotNil(t, err)
 
-	_, err = structToValues([]string{"1"})
+	_, err = structToValues(stdJSONCodec{}, []string{"1"})
 	assert.NotNil(t, err)
+
+	values, err := structToValues(stdJSONCodec{}, (*GetGameHighScoresOptions)(nil))
+	require.Nil(t, err)
+	require.Empty(t, values)
+
+	params := newSendPhotoParams("-100", "", &SendPhotoOptions{
+		Caption:         `/start "quoted" <b>path/`,
+		ParseMode:       PARSE_MODE_HTML,
+		CaptionEntities: []MessageEntity{{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 6}},
+		ReplyParameters: &ReplyParameters{MessageID: 10},
+		ReplyMarkup: &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+			{{Text: "/ok", CallbackData: `"a"`}},
+		}},
+	})
+
+	values, err = structToValues(stdJSONCodec{}, params)
+	require.Nil(t, err)
+	require.Equal(t, url.Values{
+		"chat_id":          {"-100"},
+		"caption":          {`/start "quoted" <b>path/`},
+		"parse_mode":       {"HTML"},
+		"caption_entities": {`[{"type":"bold","offset":0,"length":6}]`},
+		"reply_parameters": {`{"message_id":10}`},
+		"reply_markup":     {`{"inline_keyboard":[[{"text":"/ok","callback_data":"\"a\""}]]}`},
+	}, values)
+
+	// Form values carry the same data as JSON request
+	data, err := json.Marshal(params)
+	require.Nil(t, err)
+	fields := map[string]json.RawMessage{}
+	require.Nil(t, json.Unmarshal(data, &fields))
+	require.Equal(t, len(fields), len(values))
+	for key, raw := range fields {
+		if raw[0] == '"' {
+			s := ""
+			require.Nil(t, json.Unmarshal(raw, &s))
+			require.Equal(t, s, values.Get(key))
+		} else {
+			require.JSONEq(t, string(raw), values.Get(key))
+		}
+	}
+}
+
+type testFormEmbedded struct {
+	Name  string `json:"name"`
+	Inner int    `json:"inner"`
+}
+
+type testForm struct {
+	*testFormEmbedded
+	Name     string            `json:"name"`
+	Skipped  string            `json:"-"`
+	Float    float64           `json:"float"`
+	Uint     uint8             `json:"uint"`
+	Flag     bool              `json:"flag"`
+	Pointer  *int              `json:"pointer"`
+	Empty    string            `json:"empty,omitempty"`
+	Nil      []string          `json:"nil"`
+	Map      map[string]string `json:"map,omitempty"`
+	Untagged string
+	private  string
+}
+
+func TestStructToValuesFields(t *testing.T) {
+	number := 7
+	values, err := structToValues(stdJSONCodec{}, testForm{
+		testFormEmbedded: &testFormEmbedded{Name: "inner", Inner: 1},
+		Name:             "outer",
+		Skipped:          "x",
+		Float:            1.5,
+		Uint:             3,
+		Pointer:          &number,
+		Map:              map[string]string{"a": "b"},
+		Untagged:         "u",
+		private:          "p",
+	})
+	require.Nil(t, err)
+	require.Equal(t, url.Values{
+		"name":     {"outer"},
+		"inner":    {"1"},
+		"float":    {"1.5"},
+		"uint":     {"3"},
+		"flag":     {"false"},
+		"pointer":  {"7"},
+		"map":      {`{"a":"b"}`},
+		"Untagged": {"u"},
+	}, values)
+}
+
+func TestSplitMessageIDs(t *testing.T) {
+	require.Equal(t, [][]int64{}, SplitMessageIDs(nil, 2))
+	require.Equal(t, [][]int64{{1, 2}, {3, 4}, {5}}, SplitMessageIDs([]int64{1, 2, 3, 4, 5}, 2))
+	require.Equal(t, [][]int64{{1, 2}}, SplitMessageIDs([]int64{1, 2}, 2))
+
+	// Ids are sorted and deduplicated, the passed slice is kept as is
+	unsorted := []int64{5, 3, 5, 1, 3, 4}
+	require.Equal(t, [][]int64{{1, 3}, {4, 5}}, SplitMessageIDs(unsorted, 2))
+	require.Equal(t, []int64{5, 3, 5, 1, 3, 4}, unsorted)
+
+	ids := make([]int64, 250)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	chunks := SplitMessageIDs(ids, 0)
+	require.Equal(t, 3, len(chunks))
+	require.Equal(t, 100, len(chunks[0]))
+	require.Equal(t, 50, len(chunks[2]))
+	require.Equal(t, int64(249), chunks[2][49])
 }
@@ -3,7 +3,6 @@ package micha
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -46,15 +45,16 @@ func newGetRequest(ctx context.Context, url string, params url.Values) (*http.Re
 	return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 }
 
-func newPostRequest(ctx context.Context, url string, data interface{}) (*http.Request, error) {
-	body := new(bytes.Buffer)
+func newPostRequest(ctx context.Context, codec JSONCodec, url string, data interface{}) (*http.Request, error) {
+	var body []byte
 	if data != nil {
-		if err := json.NewEncoder(body).Encode(data); err != nil {
+		var err error
+		if body, err = codec.Marshal(data); err != nil {
 			return nil, fmt.Errorf("encode data error: %w", err)
 		}
 	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -64,11 +64,11 @@ func newPostRequest(ctx context.Context, url string, data interface{}) (*http.Re
 	return request, nil
 }
 
-func newMultipartRequest(ctx context.Context, url string, file *fileField, params url.Values) (*http.Request, error) {
+func newMultipartRequest(ctx context.Context, url string, files []*fileField, params url.Values) (*http.Request, error) {
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
 
-	if file != nil {
+	for _, file := range files {
 		part, err := writer.CreateFormFile(file.Fieldname, file.Filename)
 		if err != nil {
 			return nil, err
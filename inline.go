@@ -0,0 +1,273 @@
+package micha
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// MaxInlineQueryResults - max number of results per answerInlineQuery call
+const MaxInlineQueryResults = 50
+
+var (
+	ErrInvalidInlineOffset = errors.New("invalid inline query offset")
+)
+
+// NewInlineQueryResultArticle - create article result
+func NewInlineQueryResultArticle(id, title string, content InputMessageContent) *InlineQueryResultArticle {
+	return &InlineQueryResultArticle{Type: INLINE_TYPE_RESULT_ARTICLE, ID: id, Title: title, InputMessageContent: content}
+}
+
+// NewInlineQueryResultPhoto - create photo result
+func NewInlineQueryResultPhoto(id, photoURL, thumbnailURL string) *InlineQueryResultPhoto {
+	return &InlineQueryResultPhoto{Type: INLINE_TYPE_RESULT_PHOTO, ID: id, PhotoURL: photoURL, ThumbnailURL: thumbnailURL}
+}
+
+// NewInlineQueryResultCachedPhoto - create photo result with file stored on the Telegram servers
+func NewInlineQueryResultCachedPhoto(id, photoFileID string) *InlineQueryResultCachedPhoto {
+	return &InlineQueryResultCachedPhoto{Type: INLINE_TYPE_RESULT_PHOTO, ID: id, PhotoFileID: photoFileID}
+}
+
+// NewInlineQueryResultGif - create animated GIF result
+func NewInlineQueryResultGif(id, gifURL, thumbnailURL string) *InlineQueryResultGif {
+	return &InlineQueryResultGif{Type: INLINE_TYPE_RESULT_GIF, ID: id, GifURL: gifURL, ThumbnailURL: thumbnailURL}
+}
+
+// NewInlineQueryResultCachedGif - create animated GIF result with file stored on the Telegram servers
+func NewInlineQueryResultCachedGif(id, gifFileID string) *InlineQueryResultCachedGif {
+	return &InlineQueryResultCachedGif{Type: INLINE_TYPE_RESULT_GIF, ID: id, GifFileID: gifFileID}
+}
+
+// NewInlineQueryResultMpeg4Gif - create video animation result
+func NewInlineQueryResultMpeg4Gif(id, mpeg4URL, thumbnailURL string) *InlineQueryResultMpeg4Gif {
+	return &InlineQueryResultMpeg4Gif{Type: INLINE_TYPE_RESULT_MPEG4_GIF, ID: id, Mpeg4URL: mpeg4URL, ThumbnailURL: thumbnailURL}
+}
+
+// NewInlineQueryResultCachedMpeg4Gif - create video animation result with file stored on the Telegram servers
+func NewInlineQueryResultCachedMpeg4Gif(id, mpeg4FileID string) *InlineQueryResultCachedMpeg4Gif {
+	return &InlineQueryResultCachedMpeg4Gif{Type: INLINE_TYPE_RESULT_MPEG4_GIF, ID: id, Mpeg4FileID: mpeg4FileID}
+}
+
+// NewInlineQueryResultVideo - create video result, mimeType is "text/html" or "video/mp4"
+func NewInlineQueryResultVideo(id, videoURL, mimeType, thumbnailURL, title string) *InlineQueryResultVideo {
+	return &InlineQueryResultVideo{Type: INLINE_TYPE_RESULT_VIDEO, ID: id, VideoURL: videoURL, MimeType: mimeType, ThumbnailURL: thumbnailURL, Title: title}
+}
+
+// NewInlineQueryResultCachedVideo - create video result with file stored on the Telegram servers
+func NewInlineQueryResultCachedVideo(id, videoFileID, title string) *InlineQueryResultCachedVideo {
+	return &InlineQueryResultCachedVideo{Type: INLINE_TYPE_RESULT_VIDEO, ID: id, VideoFileID: videoFileID, Title: title}
+}
+
+// NewInlineQueryResultAudio - create mp3 audio result
+func NewInlineQueryResultAudio(id, audioURL, title string) *InlineQueryResultAudio {
+	return &InlineQueryResultAudio{Type: INLINE_TYPE_RESULT_AUDIO, ID: id, AudioURL: audioURL, Title: title}
+}
+
+// NewInlineQueryResultCachedAudio - create mp3 audio result with file stored on the Telegram servers
+func NewInlineQueryResultCachedAudio(id, audioFileID string) *InlineQueryResultCachedAudio {
+	return &InlineQueryResultCachedAudio{Type: INLINE_TYPE_RESULT_AUDIO, ID: id, AudioFileID: audioFileID}
+}
+
+// NewInlineQueryResultVoice - create voice recording result
+func NewInlineQueryResultVoice(id, voiceURL, title string) *InlineQueryResultVoice {
+	return &InlineQueryResultVoice{Type: INLINE_TYPE_RESULT_VOICE, ID: id, VoiceURL: voiceURL, Title: title}
+}
+
+// NewInlineQueryResultCachedVoice - create voice message result with file stored on the Telegram servers
+func NewInlineQueryResultCachedVoice(id, voiceFileID, title string) *InlineQueryResultCachedVoice {
+	return &InlineQueryResultCachedVoice{Type: INLINE_TYPE_RESULT_VOICE, ID: id, VoiceFileID: voiceFileID, Title: title}
+}
+
+// NewInlineQueryResultDocument - create file result, mimeType is "application/pdf" or "application/zip"
+func NewInlineQueryResultDocument(id, title, documentURL, mimeType string) *InlineQueryResultDocument {
+	return &InlineQueryResultDocument{Type: INLINE_TYPE_RESULT_DOCUMENT, ID: id, Title: title, DocumentURL: documentURL, MimeType: mimeType}
+}
+
+// NewInlineQueryResultCachedDocument - create file result with file stored on the Telegram servers
+func NewInlineQueryResultCachedDocument(id, title, documentFileID string) *InlineQueryResultCachedDocument {
+	return &InlineQueryResultCachedDocument{Type: INLINE_TYPE_RESULT_DOCUMENT, ID: id, Title: title, DocumentFileID: documentFileID}
+}
+
+// NewInlineQueryResultLocation - create location result
+func NewInlineQueryResultLocation(id string, latitude, longitude float64, title string) *InlineQueryResultLocation {
+	return &InlineQueryResultLocation{Type: INLINE_TYPE_RESULT_LOCATION, ID: id, Latitude: latitude, Longitude: longitude, Title: title}
+}
+
+// NewInlineQueryResultVenue - create venue result
+func NewInlineQueryResultVenue(id string, latitude, longitude float64, title, address string) *InlineQueryResultVenue {
+	return &InlineQueryResultVenue{Type: INLINE_TYPE_RESULT_VENUE, ID: id, Latitude: latitude, Longitude: longitude, Title: title, Address: address}
+}
+
+// NewInlineQueryResultCachedSticker - create sticker result with file stored on the Telegram servers
+func NewInlineQueryResultCachedSticker(id, stickerFileID string) *InlineQueryResultCachedSticker {
+	return &InlineQueryResultCachedSticker{Type: INLINE_TYPE_RESULT_STICKER, ID: id, StickerFileID: stickerFileID}
+}
+
+// NewInlineQueryResultContact - create contact result
+func NewInlineQueryResultContact(id, phoneNumber, firstName string) *InlineQueryResultContact {
+	return &InlineQueryResultContact{Type: INLINE_TYPE_RESULT_CONTACT, ID: id, PhoneNumber: phoneNumber, FirstName: firstName}
+}
+
+// NewInlineQueryResultGame - create game result
+func NewInlineQueryResultGame(id, gameShortName string) *InlineQueryResultGame {
+	return &InlineQueryResultGame{Type: INLINE_TYPE_RESULT_GAME, ID: id, GameShortName: gameShortName}
+}
+
+// NewInlineQueryResultsButtonWebApp - create button that launches Web App
+func NewInlineQueryResultsButtonWebApp(text, url string) *InlineQueryResultsButton {
+	return &InlineQueryResultsButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// NewInlineQueryResultsButtonStart - create button that starts private chat with the bot with /start startParameter
+func NewInlineQueryResultsButtonStart(text, startParameter string) *InlineQueryResultsButton {
+	return &InlineQueryResultsButton{Text: text, StartParameter: startParameter}
+}
+
+// InlineResultID - return ID of inline query result
+func InlineResultID(result InlineQueryResult) string {
+	v := reflect.Indirect(reflect.ValueOf(result))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	id := v.FieldByName("ID")
+	if id.Kind() != reflect.String {
+		return ""
+	}
+
+	return id.String()
+}
+
+// InlineResultTracker remembers source items of sent inline results
+// to resolve ChosenInlineResult.ResultID back to the item.
+// Chosen results are sent only if inline feedback is enabled via @BotFather.
+type InlineResultTracker[T any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]T
+	order    []string
+}
+
+// DefaultInlineResultTrackerSize - default number of results remembered by InlineResultTracker
+const DefaultInlineResultTrackerSize = MaxInlineQueryResults * 100
+
+// NewInlineResultTracker - create tracker remembering up to capacity last results
+// (DefaultInlineResultTrackerSize if capacity is 0)
+func NewInlineResultTracker[T any](capacity int) *InlineResultTracker[T] {
+	if capacity <= 0 {
+		capacity = DefaultInlineResultTrackerSize
+	}
+
+	return &InlineResultTracker[T]{
+		capacity: capacity,
+		items:    map[string]T{},
+	}
+}
+
+// Remember - remember item of result
+func (t *InlineResultTracker[T]) Remember(resultID string, item T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.items[resultID]; !ok {
+		t.order = append(t.order, resultID)
+	}
+	t.items[resultID] = item
+
+	if len(t.order) > t.capacity {
+		delete(t.items, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+// Resolve - return item of chosen result
+func (t *InlineResultTracker[T]) Resolve(result ChosenInlineResult) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	item, ok := t.items[result.ResultID]
+	return item, ok
+}
+
+// InlineSource - return up to limit items for query starting from offset
+type InlineSource[T any] func(query InlineQuery, offset, limit int) ([]T, error)
+
+// InlinePaginator turns a data source into pages of inline query results.
+// Offsets are passed to Telegram as next_offset and come back with the next query.
+type InlinePaginator[T any] struct {
+	pageSize int
+	source   InlineSource[T]
+	result   func(item T) InlineQueryResult
+	tracker  *InlineResultTracker[T]
+}
+
+// NewInlinePaginator - create paginator, pageSize is capped to MaxInlineQueryResults
+func NewInlinePaginator[T any](pageSize int, source InlineSource[T], result func(item T) InlineQueryResult) *InlinePaginator[T] {
+	if pageSize <= 0 || pageSize > MaxInlineQueryResults {
+		pageSize = MaxInlineQueryResults
+	}
+
+	return &InlinePaginator[T]{
+		pageSize: pageSize,
+		source:   source,
+		result:   result,
+	}
+}
+
+// Track - remember items of returned results in tracker
+func (p *InlinePaginator[T]) Track(tracker *InlineResultTracker[T]) *InlinePaginator[T] {
+	p.tracker = tracker
+	return p
+}
+
+// Page - return results for query and next offset (empty if there are no more results)
+func (p *InlinePaginator[T]) Page(query InlineQuery) (InlineQueryResults, string, error) {
+	offset := 0
+	if query.Offset != "" {
+		var err error
+		offset, err = strconv.Atoi(query.Offset)
+		if err != nil || offset < 0 {
+			return nil, "", ErrInvalidInlineOffset
+		}
+	}
+
+	// Request one extra item to know if there is next page
+	items, err := p.source(query, offset, p.pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextOffset := ""
+	if len(items) > p.pageSize {
+		items = items[:p.pageSize]
+		nextOffset = strconv.Itoa(offset + p.pageSize)
+	}
+
+	results := make(InlineQueryResults, 0, len(items))
+	for _, item := range items {
+		result := p.result(item)
+		if p.tracker != nil {
+			p.tracker.Remember(InlineResultID(result), item)
+		}
+		results = append(results, result)
+	}
+
+	return results, nextOffset, nil
+}
+
+// Answer - answer inline query with the page of results
+func (p *InlinePaginator[T]) Answer(bot *Bot, query InlineQuery, options *AnswerInlineQueryOptions) error {
+	results, nextOffset, err := p.Page(query)
+	if err != nil {
+		return err
+	}
+
+	opts := AnswerInlineQueryOptions{}
+	if options != nil {
+		opts = *options
+	}
+	opts.NextOffset = nextOffset
+
+	return bot.AnswerInlineQuery(query.ID, results, &opts)
+}
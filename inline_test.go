@@ -0,0 +1,111 @@
+package micha
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineQueryResultConstructors(t *testing.T) {
+	for _, c := range []struct {
+		result     InlineQueryResult
+		resultType InlineResultType
+	}{
+		{NewInlineQueryResultArticle("1", "title", InputTextMessageContent{MessageText: "text"}), INLINE_TYPE_RESULT_ARTICLE},
+		{NewInlineQueryResultPhoto("1", "https://example.com/p.jpg", "https://example.com/t.jpg"), INLINE_TYPE_RESULT_PHOTO},
+		{NewInlineQueryResultCachedPhoto("1", "file"), INLINE_TYPE_RESULT_PHOTO},
+		{NewInlineQueryResultGif("1", "https://example.com/a.gif", "https://example.com/t.jpg"), INLINE_TYPE_RESULT_GIF},
+		{NewInlineQueryResultCachedGif("1", "file"), INLINE_TYPE_RESULT_GIF},
+		{NewInlineQueryResultMpeg4Gif("1", "https://example.com/a.mp4", "https://example.com/t.jpg"), INLINE_TYPE_RESULT_MPEG4_GIF},
+		{NewInlineQueryResultCachedMpeg4Gif("1", "file"), INLINE_TYPE_RESULT_MPEG4_GIF},
+		{NewInlineQueryResultVideo("1", "https://example.com/v.mp4", "video/mp4", "https://example.com/t.jpg", "title"), INLINE_TYPE_RESULT_VIDEO},
+		{NewInlineQueryResultCachedVideo("1", "file", "title"), INLINE_TYPE_RESULT_VIDEO},
+		{NewInlineQueryResultAudio("1", "https://example.com/a.mp3", "title"), INLINE_TYPE_RESULT_AUDIO},
+		{NewInlineQueryResultCachedAudio("1", "file"), INLINE_TYPE_RESULT_AUDIO},
+		{NewInlineQueryResultVoice("1", "https://example.com/v.ogg", "title"), INLINE_TYPE_RESULT_VOICE},
+		{NewInlineQueryResultCachedVoice("1", "file", "title"), INLINE_TYPE_RESULT_VOICE},
+		{NewInlineQueryResultDocument("1", "title", "https://example.com/d.pdf", "application/pdf"), INLINE_TYPE_RESULT_DOCUMENT},
+		{NewInlineQueryResultCachedDocument("1", "title", "file"), INLINE_TYPE_RESULT_DOCUMENT},
+		{NewInlineQueryResultLocation("1", 1.5, 2.5, "title"), INLINE_TYPE_RESULT_LOCATION},
+		{NewInlineQueryResultVenue("1", 1.5, 2.5, "title", "address"), INLINE_TYPE_RESULT_VENUE},
+		{NewInlineQueryResultCachedSticker("1", "file"), INLINE_TYPE_RESULT_STICKER},
+		{NewInlineQueryResultContact("1", "+123", "John"), INLINE_TYPE_RESULT_CONTACT},
+		{NewInlineQueryResultGame("1", "game"), INLINE_TYPE_RESULT_GAME},
+	} {
+		data, err := json.Marshal(c.result)
+		require.Nil(t, err)
+
+		result := struct {
+			Type InlineResultType `json:"type"`
+			ID   string           `json:"id"`
+		}{}
+		require.Nil(t, json.Unmarshal(data, &result))
+		require.Equal(t, c.resultType, result.Type)
+		require.Equal(t, "1", result.ID)
+		require.Equal(t, "1", InlineResultID(c.result))
+	}
+}
+
+func TestInlinePaginator(t *testing.T) {
+	source := func(query InlineQuery, offset, limit int) ([]int, error) {
+		if query.Query == "fail" {
+			return nil, errors.New("fail")
+		}
+
+		items := []int{}
+		for i := offset; i < 120 && len(items) < limit; i++ {
+			items = append(items, i)
+		}
+		return items, nil
+	}
+	render := func(item int) InlineQueryResult {
+		return NewInlineQueryResultArticle(strconv.Itoa(item), "", nil)
+	}
+
+	tracker := NewInlineResultTracker[int](60)
+	paginator := NewInlinePaginator(100, source, render).Track(tracker)
+
+	results, nextOffset, err := paginator.Page(InlineQuery{})
+	require.Nil(t, err)
+	require.Len(t, results, MaxInlineQueryResults)
+	require.Equal(t, "50", nextOffset)
+
+	results, nextOffset, err = paginator.Page(InlineQuery{Offset: nextOffset})
+	require.Nil(t, err)
+	require.Len(t, results, 50)
+	require.Equal(t, "50", InlineResultID(results[0]))
+	require.Equal(t, "100", nextOffset)
+
+	results, nextOffset, err = paginator.Page(InlineQuery{Offset: nextOffset})
+	require.Nil(t, err)
+	require.Len(t, results, 20)
+	require.Equal(t, "", nextOffset)
+
+	_, _, err = paginator.Page(InlineQuery{Offset: "x"})
+	require.ErrorIs(t, err, ErrInvalidInlineOffset)
+	_, _, err = paginator.Page(InlineQuery{Query: "fail"})
+	require.NotNil(t, err)
+
+	item, ok := tracker.Resolve(ChosenInlineResult{ResultID: "119"})
+	require.True(t, ok)
+	require.Equal(t, 119, item)
+
+	// Oldest results are forgotten
+	_, ok = tracker.Resolve(ChosenInlineResult{ResultID: "10"})
+	require.False(t, ok)
+	item, ok = tracker.Resolve(ChosenInlineResult{ResultID: "60"})
+	require.True(t, ok)
+	require.Equal(t, 60, item)
+
+	// Zero capacity means default limit, not unlimited
+	tracker = NewInlineResultTracker[int](0)
+	for i := 0; i <= DefaultInlineResultTrackerSize; i++ {
+		tracker.Remember(strconv.Itoa(i), i)
+	}
+	require.Len(t, tracker.items, DefaultInlineResultTrackerSize)
+	_, ok = tracker.Resolve(ChosenInlineResult{ResultID: "0"})
+	require.False(t, ok)
+}
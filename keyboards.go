@@ -0,0 +1,380 @@
+package micha
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MaxCallbackDataLength - max size of callback_data in bytes
+const MaxCallbackDataLength = 64
+
+var (
+	ErrButtonEmptyText       = errors.New("button text is empty")
+	ErrButtonNoAction        = errors.New("button has no action")
+	ErrButtonMultipleActions = errors.New("button has more than one action")
+	ErrButtonPosition        = errors.New("pay and game buttons must be the first button in the first row")
+	ErrCallbackDataTooLong   = fmt.Errorf("callback data is longer than %d bytes", MaxCallbackDataLength)
+)
+
+// NewInlineButtonCallback - create button that sends callback query with data
+func NewInlineButtonCallback(text, data string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: data}
+}
+
+// NewInlineButtonURL - create button that opens url
+func NewInlineButtonURL(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, URL: url}
+}
+
+// NewInlineButtonLoginURL - create button that authorizes user on the website
+func NewInlineButtonLoginURL(text string, loginURL LoginURL) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, LoginURL: &loginURL}
+}
+
+// NewInlineButtonWebApp - create button that launches Web App
+func NewInlineButtonWebApp(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// NewInlineButtonSwitchInline - create button that prompts user to select a chat and inserts bot's username and query.
+// Query may be empty, then only the bot's username is inserted.
+func NewInlineButtonSwitchInline(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQuery: query, emptySwitchInlineQuery: query == ""}
+}
+
+// NewInlineButtonSwitchInlineCurrentChat - create button that inserts bot's username and query in the current chat's input field.
+// Query may be empty, then only the bot's username is inserted.
+func NewInlineButtonSwitchInlineCurrentChat(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryCurrentChat: query, emptySwitchInlineQueryCurrentChat: query == ""}
+}
+
+// NewInlineButtonSwitchInlineChosenChat - create button that prompts user to select a chat of the specified type
+func NewInlineButtonSwitchInlineChosenChat(text string, chosenChat SwitchInlineQueryChosenChat) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryChosenChat: &chosenChat}
+}
+
+// NewInlineButtonCopyText - create button that copies text to the clipboard
+func NewInlineButtonCopyText(text, copyText string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CopyText: &CopyTextButton{Text: copyText}}
+}
+
+// NewInlineButtonGame - create button that launches the game, must be the first button in the first row
+func NewInlineButtonGame(text string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackGame: &CallbackGame{}}
+}
+
+// NewInlineButtonPay - create pay button, must be the first button in the first row
+func NewInlineButtonPay(text string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, Pay: true}
+}
+
+// Validate - check that exactly one optional field is set and callback data fits the limit.
+// Validation is opt-in: Send* and Edit* methods don't call it, InlineKeyboardBuilder.Build does.
+func (b InlineKeyboardButton) Validate() error {
+	if b.Text == "" {
+		return ErrButtonEmptyText
+	}
+
+	actions := 0
+	for _, set := range []bool{
+		b.URL != "",
+		b.LoginURL != nil,
+		b.CallbackData != "",
+		b.WebApp != nil,
+		b.SwitchInlineQuery != "" || b.emptySwitchInlineQuery,
+		b.SwitchInlineQueryCurrentChat != "" || b.emptySwitchInlineQueryCurrentChat,
+		b.SwitchInlineQueryChosenChat != nil,
+		b.CopyText != nil,
+		b.CallbackGame != nil,
+		b.Pay,
+	} {
+		if set {
+			actions++
+		}
+	}
+
+	switch {
+	case actions == 0:
+		return ErrButtonNoAction
+	case actions > 1:
+		return ErrButtonMultipleActions
+	case len(b.CallbackData) > MaxCallbackDataLength:
+		return ErrCallbackDataTooLong
+	}
+
+	return nil
+}
+
+// MarshalJSON - encode button, empty switch inline queries of buttons created by constructors are kept
+func (b InlineKeyboardButton) MarshalJSON() ([]byte, error) {
+	type button InlineKeyboardButton
+	data, err := json.Marshal(button(b))
+	if err != nil || (!b.emptySwitchInlineQuery && !b.emptySwitchInlineQueryCurrentChat) {
+		return data, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if b.emptySwitchInlineQuery && b.SwitchInlineQuery == "" {
+		fields["switch_inline_query"] = json.RawMessage(`""`)
+	}
+	if b.emptySwitchInlineQueryCurrentChat && b.SwitchInlineQueryCurrentChat == "" {
+		fields["switch_inline_query_current_chat"] = json.RawMessage(`""`)
+	}
+
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON - decode button, empty switch inline queries are remembered
+func (b *InlineKeyboardButton) UnmarshalJSON(data []byte) error {
+	type button InlineKeyboardButton
+	if err := json.Unmarshal(data, (*button)(b)); err != nil {
+		return err
+	}
+
+	fields := struct {
+		SwitchInlineQuery            *string `json:"switch_inline_query"`
+		SwitchInlineQueryCurrentChat *string `json:"switch_inline_query_current_chat"`
+	}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	b.emptySwitchInlineQuery = fields.SwitchInlineQuery != nil && *fields.SwitchInlineQuery == ""
+	b.emptySwitchInlineQueryCurrentChat = fields.SwitchInlineQueryCurrentChat != nil && *fields.SwitchInlineQueryCurrentChat == ""
+
+	return nil
+}
+
+// Validate - check all buttons of the keyboard
+func (m InlineKeyboardMarkup) Validate() error {
+	for i, row := range m.InlineKeyboard {
+		for j, button := range row {
+			err := button.Validate()
+			if err == nil && (button.Pay || button.CallbackGame != nil) && (i != 0 || j != 0) {
+				err = ErrButtonPosition
+			}
+			if err != nil {
+				return fmt.Errorf("button %q (row %d, column %d): %w", button.Text, i, j, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewKeyboardButton - create simple text button
+func NewKeyboardButton(text string) KeyboardButton {
+	return KeyboardButton{Text: text}
+}
+
+// NewKeyboardButtonContact - create button that sends user's phone number
+func NewKeyboardButtonContact(text string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestContact: true}
+}
+
+// NewKeyboardButtonLocation - create button that sends user's current location
+func NewKeyboardButtonLocation(text string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestLocation: true}
+}
+
+// NewKeyboardButtonRequestUsers - create button that opens a list of suitable users
+func NewKeyboardButtonRequestUsers(text string, request KeyboardButtonRequestUsers) KeyboardButton {
+	return KeyboardButton{Text: text, RequestUsers: &request}
+}
+
+// NewKeyboardButtonRequestChat - create button that opens a list of suitable chats
+func NewKeyboardButtonRequestChat(text string, request KeyboardButtonRequestChat) KeyboardButton {
+	return KeyboardButton{Text: text, RequestChat: &request}
+}
+
+// NewKeyboardButtonPoll - create button that asks user to create a poll, empty pollType allows any type
+func NewKeyboardButtonPoll(text string, pollType PollType) KeyboardButton {
+	return KeyboardButton{Text: text, RequestPoll: &KeyboardButtonPollType{Type: pollType}}
+}
+
+// NewKeyboardButtonWebApp - create button that launches Web App
+func NewKeyboardButtonWebApp(text, url string) KeyboardButton {
+	return KeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// Validate - check that at most one optional field is set.
+// Validation is opt-in: Send* methods don't call it, ReplyKeyboardBuilder.Build does.
+func (b KeyboardButton) Validate() error {
+	if b.Text == "" {
+		return ErrButtonEmptyText
+	}
+
+	actions := 0
+	for _, set := range []bool{
+		b.RequestUsers != nil,
+		b.RequestChat != nil,
+		b.RequestContact,
+		b.RequestLocation,
+		b.RequestPoll != nil,
+		b.WebApp != nil,
+	} {
+		if set {
+			actions++
+		}
+	}
+
+	if actions > 1 {
+		return ErrButtonMultipleActions
+	}
+
+	return nil
+}
+
+// Validate - check all buttons of the keyboard
+func (m ReplyKeyboardMarkup) Validate() error {
+	for i, row := range m.Keyboard {
+		for j, button := range row {
+			if err := button.Validate(); err != nil {
+				return fmt.Errorf("button %q (row %d, column %d): %w", button.Text, i, j, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+type keyboardLayout[T any] struct {
+	columns int
+	rows    [][]T
+}
+
+func (l *keyboardLayout[T]) add(buttons []T) {
+	for _, button := range buttons {
+		last := len(l.rows) - 1
+		if last < 0 || (l.columns > 0 && len(l.rows[last]) >= l.columns) {
+			l.rows = append(l.rows, nil)
+			last++
+		}
+		l.rows[last] = append(l.rows[last], button)
+	}
+}
+
+func (l *keyboardLayout[T]) row(buttons []T) {
+	if len(l.rows) == 0 || len(l.rows[len(l.rows)-1]) > 0 {
+		l.rows = append(l.rows, nil)
+	}
+	l.add(buttons)
+}
+
+func (l *keyboardLayout[T]) build() [][]T {
+	rows := make([][]T, 0, len(l.rows))
+	for _, row := range l.rows {
+		if len(row) > 0 {
+			rows = append(rows, append([]T(nil), row...))
+		}
+	}
+
+	return rows
+}
+
+// InlineKeyboardBuilder - builder for InlineKeyboardMarkup
+type InlineKeyboardBuilder struct {
+	layout keyboardLayout[InlineKeyboardButton]
+}
+
+// NewInlineKeyboard - create inline keyboard builder.
+// Rows are wrapped automatically after columns buttons, zero columns disables wrapping.
+func NewInlineKeyboard(columns int) *InlineKeyboardBuilder {
+	return &InlineKeyboardBuilder{
+		layout: keyboardLayout[InlineKeyboardButton]{columns: columns},
+	}
+}
+
+// Add - append buttons to the current row
+func (b *InlineKeyboardBuilder) Add(buttons ...InlineKeyboardButton) *InlineKeyboardBuilder {
+	b.layout.add(buttons)
+	return b
+}
+
+// Row - start new row with buttons
+func (b *InlineKeyboardBuilder) Row(buttons ...InlineKeyboardButton) *InlineKeyboardBuilder {
+	b.layout.row(buttons)
+	return b
+}
+
+// Build - validate buttons and return keyboard markup
+func (b *InlineKeyboardBuilder) Build() (*InlineKeyboardMarkup, error) {
+	markup := &InlineKeyboardMarkup{
+		InlineKeyboard: b.layout.build(),
+	}
+	if err := markup.Validate(); err != nil {
+		return nil, err
+	}
+
+	return markup, nil
+}
+
+// ReplyKeyboardBuilder - builder for ReplyKeyboardMarkup
+type ReplyKeyboardBuilder struct {
+	layout keyboardLayout[KeyboardButton]
+	markup ReplyKeyboardMarkup
+}
+
+// NewReplyKeyboard - create reply keyboard builder.
+// Rows are wrapped automatically after columns buttons, zero columns disables wrapping.
+func NewReplyKeyboard(columns int) *ReplyKeyboardBuilder {
+	return &ReplyKeyboardBuilder{
+		layout: keyboardLayout[KeyboardButton]{columns: columns},
+	}
+}
+
+// Add - append buttons to the current row
+func (b *ReplyKeyboardBuilder) Add(buttons ...KeyboardButton) *ReplyKeyboardBuilder {
+	b.layout.add(buttons)
+	return b
+}
+
+// Row - start new row with buttons
+func (b *ReplyKeyboardBuilder) Row(buttons ...KeyboardButton) *ReplyKeyboardBuilder {
+	b.layout.row(buttons)
+	return b
+}
+
+// Resize - request clients to resize the keyboard vertically for optimal fit
+func (b *ReplyKeyboardBuilder) Resize() *ReplyKeyboardBuilder {
+	b.markup.ResizeKeyboard = true
+	return b
+}
+
+// OneTime - request clients to hide the keyboard as soon as it's been used
+func (b *ReplyKeyboardBuilder) OneTime() *ReplyKeyboardBuilder {
+	b.markup.OneTimeKeyboard = true
+	return b
+}
+
+// Persistent - request clients to always show the keyboard when the regular keyboard is hidden
+func (b *ReplyKeyboardBuilder) Persistent() *ReplyKeyboardBuilder {
+	b.markup.IsPersistent = true
+	return b
+}
+
+// Selective - show the keyboard to specific users only
+func (b *ReplyKeyboardBuilder) Selective() *ReplyKeyboardBuilder {
+	b.markup.Selective = true
+	return b
+}
+
+// Placeholder - set placeholder shown in the input field when the keyboard is active
+func (b *ReplyKeyboardBuilder) Placeholder(placeholder string) *ReplyKeyboardBuilder {
+	b.markup.InputFieldPlaceholder = placeholder
+	return b
+}
+
+// Build - validate buttons and return keyboard markup
+func (b *ReplyKeyboardBuilder) Build() (*ReplyKeyboardMarkup, error) {
+	markup := b.markup
+	markup.Keyboard = b.layout.build()
+	if err := markup.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &markup, nil
+}
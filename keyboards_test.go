@@ -0,0 +1,124 @@
+package micha
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineKeyboardBuilder(t *testing.T) {
+	markup, err := NewInlineKeyboard(2).
+		Add(
+			NewInlineButtonPay("Pay"),
+			NewInlineButtonCallback("1", "one"),
+			NewInlineButtonCallback("2", "two"),
+		).
+		Row(NewInlineButtonURL("Site", "https://example.com")).
+		Row().
+		Row(NewInlineButtonCopyText("Copy", "code")).
+		Build()
+	require.Nil(t, err)
+	require.Equal(t, [][]InlineKeyboardButton{
+		{NewInlineButtonPay("Pay"), NewInlineButtonCallback("1", "one")},
+		{NewInlineButtonCallback("2", "two")},
+		{NewInlineButtonURL("Site", "https://example.com")},
+		{NewInlineButtonCopyText("Copy", "code")},
+	}, markup.InlineKeyboard)
+
+	data, err := json.Marshal(markup)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"inline_keyboard":[
+		[{"text":"Pay","pay":true},{"text":"1","callback_data":"one"}],
+		[{"text":"2","callback_data":"two"}],
+		[{"text":"Site","url":"https://example.com"}],
+		[{"text":"Copy","copy_text":{"text":"code"}}]
+	]}`, string(data))
+}
+
+func TestInlineKeyboardValidation(t *testing.T) {
+	_, err := NewInlineKeyboard(0).Add(NewInlineButtonCallback("ok", strings.Repeat("x", MaxCallbackDataLength))).Build()
+	require.Nil(t, err)
+
+	_, err = NewInlineKeyboard(0).Add(NewInlineButtonCallback("long", strings.Repeat("я", 33))).Build()
+	require.ErrorIs(t, err, ErrCallbackDataTooLong)
+
+	_, err = NewInlineKeyboard(0).Add(InlineKeyboardButton{Text: "none"}).Build()
+	require.ErrorIs(t, err, ErrButtonNoAction)
+
+	_, err = NewInlineKeyboard(0).Add(InlineKeyboardButton{Text: "two", URL: "https://example.com", Pay: true}).Build()
+	require.ErrorIs(t, err, ErrButtonMultipleActions)
+
+	_, err = NewInlineKeyboard(0).Add(NewInlineButtonCallback("", "data")).Build()
+	require.ErrorIs(t, err, ErrButtonEmptyText)
+
+	_, err = NewInlineKeyboard(0).Add(NewInlineButtonCallback("1", "one"), NewInlineButtonGame("Play")).Build()
+	require.ErrorIs(t, err, ErrButtonPosition)
+	require.Contains(t, err.Error(), `"Play" (row 0, column 1)`)
+
+	// Empty inline queries are valid actions
+	for _, c := range []struct {
+		button InlineKeyboardButton
+		json   string
+	}{
+		{NewInlineButtonSwitchInline("Share", ""), `{"text":"Share","switch_inline_query":""}`},
+		{NewInlineButtonSwitchInlineCurrentChat("Search", ""), `{"text":"Search","switch_inline_query_current_chat":""}`},
+		{NewInlineButtonSwitchInlineCurrentChat("Search", "cats"), `{"text":"Search","switch_inline_query_current_chat":"cats"}`},
+	} {
+		require.Nil(t, c.button.Validate())
+		data, err := json.Marshal(c.button)
+		require.Nil(t, err)
+		require.JSONEq(t, c.json, string(data))
+
+		button := InlineKeyboardButton{}
+		require.Nil(t, json.Unmarshal(data, &button))
+		require.Equal(t, c.button, button)
+	}
+
+	_, err = NewInlineKeyboard(0).Add(NewInlineButtonSwitchInlineCurrentChat("Search", "")).Build()
+	require.Nil(t, err)
+	require.ErrorIs(t, InlineKeyboardButton{Text: "Search", SwitchInlineQueryCurrentChat: ""}.Validate(), ErrButtonNoAction)
+}
+
+func TestReplyKeyboardBuilder(t *testing.T) {
+	markup, err := NewReplyKeyboard(3).
+		Add(
+			NewKeyboardButton("A"),
+			NewKeyboardButtonContact("Phone"),
+			NewKeyboardButtonLocation("Location"),
+			NewKeyboardButtonPoll("Quiz", POLL_TYPE_QUIZ),
+		).
+		Row(
+			NewKeyboardButtonRequestUsers("Users", KeyboardButtonRequestUsers{RequestID: 1, MaxQuantity: 2}),
+			NewKeyboardButtonRequestChat("Chat", KeyboardButtonRequestChat{RequestID: 2, ChatIsChannel: true}),
+			NewKeyboardButtonWebApp("App", "https://example.com/app"),
+		).
+		Resize().
+		OneTime().
+		Persistent().
+		Placeholder("Choose").
+		Build()
+	require.Nil(t, err)
+
+	data, err := json.Marshal(markup)
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"keyboard":[
+			[{"text":"A"},{"text":"Phone","request_contact":true},{"text":"Location","request_location":true}],
+			[{"text":"Quiz","request_poll":{"type":"quiz"}}],
+			[
+				{"text":"Users","request_users":{"request_id":1,"max_quantity":2}},
+				{"text":"Chat","request_chat":{"request_id":2,"chat_is_channel":true}},
+				{"text":"App","web_app":{"url":"https://example.com/app"}}
+			]
+		],
+		"resize_keyboard":true,
+		"one_time_keyboard":true,
+		"is_persistent":true,
+		"input_field_placeholder":"Choose"
+	}`, string(data))
+
+	_, err = NewReplyKeyboard(0).Add(KeyboardButton{Text: "Both", RequestContact: true, RequestLocation: true}).Build()
+	require.ErrorIs(t, err, ErrButtonMultipleActions)
+}
@@ -0,0 +1,78 @@
+package micha
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// LoginWidgetUser - user authorized with Telegram Login Widget
+type LoginWidgetUser struct {
+	ID        int64     `json:"id"`
+	FirstName string    `json:"first_name"`
+	AuthTime  time.Time `json:"auth_time"`
+
+	// Optional
+	LastName string `json:"last_name,omitempty"`
+	Username string `json:"username,omitempty"`
+	PhotoURL string `json:"photo_url,omitempty"`
+}
+
+// ParseLoginWidget - validate Login Widget callback values and return authorized user.
+// Data older than maxAge is rejected to prevent replays, zero maxAge disables the check.
+func ParseLoginWidget(values url.Values, botToken string, maxAge time.Duration) (*LoginWidgetUser, error) {
+	if err := ValidateAuthCallback(values, botToken); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(values.Get("id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id: %w", err)
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth_date: %w", err)
+	}
+
+	user := &LoginWidgetUser{
+		ID:        id,
+		FirstName: values.Get("first_name"),
+		AuthTime:  time.Unix(authDate, 0),
+		LastName:  values.Get("last_name"),
+		Username:  values.Get("username"),
+		PhotoURL:  values.Get("photo_url"),
+	}
+	if maxAge > 0 && time.Since(user.AuthTime) > maxAge {
+		return nil, ErrAuthDateExpired
+	}
+
+	return user, nil
+}
+
+type loginWidgetContextKey struct{}
+
+// LoginWidgetMiddleware - net/http middleware for the Login Widget redirect endpoint (data-auth-url).
+// Requests with invalid or expired query are rejected with 401 status.
+func LoginWidgetMiddleware(botToken string, maxAge time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := ParseLoginWidget(r.URL.Query(), botToken, maxAge)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), loginWidgetContextKey{}, user)))
+		})
+	}
+}
+
+// LoginWidgetUserFromContext - return user stored by LoginWidgetMiddleware
+func LoginWidgetUserFromContext(ctx context.Context) (*LoginWidgetUser, bool) {
+	user, ok := ctx.Value(loginWidgetContextKey{}).(*LoginWidgetUser)
+	return user, ok
+}
@@ -0,0 +1,57 @@
+package micha
+
+import (
+	"io"
+)
+
+// InputFile - file uploaded with multipart request and referenced in media as "attach://<Name>"
+type InputFile struct {
+	Name     string // Unique name of the file in the request
+	Source   io.Reader
+	FileName string
+}
+
+// NewInputFile - create file to upload under name
+func NewInputFile(name string, source io.Reader, fileName string) InputFile {
+	return InputFile{Name: name, Source: source, FileName: fileName}
+}
+
+// Attach - return reference to the file for media and thumbnail fields
+func (f InputFile) Attach() string {
+	return "attach://" + f.Name
+}
+
+// NewInputMediaPhoto - create photo media, media is file_id, HTTP URL or "attach://<name>"
+func NewInputMediaPhoto(media string) *InputMediaPhoto {
+	return &InputMediaPhoto{Type: INPUT_MEDIA_TYPE_PHOTO, Media: media}
+}
+
+// NewInputMediaVideo - create video media, media is file_id, HTTP URL or "attach://<name>"
+func NewInputMediaVideo(media string) *InputMediaVideo {
+	return &InputMediaVideo{Type: INPUT_MEDIA_TYPE_VIDEO, Media: media}
+}
+
+// NewInputMediaAnimation - create animation media, media is file_id, HTTP URL or "attach://<name>"
+func NewInputMediaAnimation(media string) *InputMediaAnimation {
+	return &InputMediaAnimation{Type: INPUT_MEDIA_TYPE_ANIMATION, Media: media}
+}
+
+// NewInputMediaAudio - create audio media, media is file_id, HTTP URL or "attach://<name>"
+func NewInputMediaAudio(media string) *InputMediaAudio {
+	return &InputMediaAudio{Type: INPUT_MEDIA_TYPE_AUDIO, Media: media}
+}
+
+// NewInputMediaDocument - create document media, media is file_id, HTTP URL or "attach://<name>"
+func NewInputMediaDocument(media string) *InputMediaDocument {
+	return &InputMediaDocument{Type: INPUT_MEDIA_TYPE_DOCUMENT, Media: media}
+}
+
+// NewInputPaidMediaPhoto - create paid photo, media is file_id, HTTP URL or "attach://<name>"
+func NewInputPaidMediaPhoto(media string) InputPaidMedia {
+	return InputPaidMedia{Type: INPUT_MEDIA_TYPE_PHOTO, Media: media}
+}
+
+// NewInputPaidMediaVideo - create paid video, media is file_id, HTTP URL or "attach://<name>"
+func NewInputPaidMediaVideo(media string) InputPaidMedia {
+	return InputPaidMedia{Type: INPUT_MEDIA_TYPE_VIDEO, Media: media}
+}
@@ -11,6 +11,7 @@ type Options struct {
 	logger     Logger
 	apiServer  string
 	httpClient HttpClient
+	jsonCodec  JSONCodec
 	ctx        context.Context
 }
 
@@ -46,6 +47,14 @@ func WithHttpClient(httpClient HttpClient) Option {
 	}
 }
 
+// WithJSONCodec - set JSON codec for requests and responses
+// Defaults to encoding/json.
+func WithJSONCodec(codec JSONCodec) Option {
+	return func(o *Options) {
+		o.jsonCodec = codec
+	}
+}
+
 // WithAPIServer - set custom api server (https://github.com/tdlib/telegram-bot-api)
 func WithAPIServer(url string) Option {
 	return func(o *Options) {
@@ -62,117 +71,297 @@ func WithCtx(ctx context.Context) Option {
 
 // SendMessageOptions optional params SendMessage method
 type SendMessageOptions struct {
-	ParseMode             ParseMode   `json:"parse_mode,omitempty"`
-	DisableWebPagePreview bool        `json:"disable_web_page_preview,omitempty"`
-	DisableNotification   bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID      int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup           ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID  string              `json:"business_connection_id,omitempty"`
+	ParseMode             ParseMode           `json:"parse_mode,omitempty"`
+	Entities              []MessageEntity     `json:"entities,omitempty"`
+	LinkPreviewOptions    *LinkPreviewOptions `json:"link_preview_options,omitempty"`
+	DisableWebPagePreview bool                `json:"disable_web_page_preview,omitempty"` // Deprecated: use LinkPreviewOptions
+	DisableNotification   bool                `json:"disable_notification,omitempty"`
+	ProtectContent        bool                `json:"protect_content,omitempty"`
+	MessageEffectID       string              `json:"message_effect_id,omitempty"`
+	ReplyParameters       *ReplyParameters    `json:"reply_parameters,omitempty"`
+	ReplyToMessageID      int64               `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup           ReplyMarkup         `json:"reply_markup,omitempty"`
 }
 
 // SendPhotoOptions optional params SendPhoto method
 type SendPhotoOptions struct {
-	Caption             string      `json:"caption,omitempty"`
-	ParseMode           ParseMode   `json:"parse_mode,omitempty"`
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID  string           `json:"business_connection_id,omitempty"`
+	Caption               string           `json:"caption,omitempty"`
+	ParseMode             ParseMode        `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity  `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool             `json:"show_caption_above_media,omitempty"`
+	DisableNotification   bool             `json:"disable_notification,omitempty"`
+	ProtectContent        bool             `json:"protect_content,omitempty"`
+	MessageEffectID       string           `json:"message_effect_id,omitempty"`
+	ReplyParameters       *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID      int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup           ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // SendAudioOptions optional params SendAudio method
 type SendAudioOptions struct {
-	Caption             string      `json:"caption,omitempty"`
-	ParseMode           ParseMode   `json:"parse_mode,omitempty"`
-	Duration            int         `json:"duration,omitempty"`
-	Performer           string      `json:"performer,omitempty"`
-	Title               string      `json:"title,omitempty"`
-	Thumb               string      `json:"thumb,omitempty"` // TODO add thumb as file
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ProtectContent      bool        `json:"protect_content,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	Caption              string           `json:"caption,omitempty"`
+	ParseMode            ParseMode        `json:"parse_mode,omitempty"`
+	CaptionEntities      []MessageEntity  `json:"caption_entities,omitempty"`
+	Duration             Seconds          `json:"duration,omitempty"`
+	Performer            string           `json:"performer,omitempty"`
+	Title                string           `json:"title,omitempty"`
+	Thumbnail            string           `json:"thumbnail,omitempty"` // "attach://<file_attach_name>" of a thumbnail uploaded in the same request, set by *File methods for ThumbnailFile
+	ThumbnailFile        *InputFile       `json:"-"`                   // Thumbnail uploaded by *File methods
+	Thumb                string           `json:"-"`                   // Deprecated: use Thumbnail
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // SendDocumentOptions optional params SendDocument method
 type SendDocumentOptions struct {
-	Thumb               string      `json:"thumb,omitempty"` // TODO add thumb as file
-	Caption             string      `json:"caption,omitempty"`
-	ParseMode           ParseMode   `json:"parse_mode,omitempty"`
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	Thumbnail            string           `json:"thumbnail,omitempty"` // "attach://<file_attach_name>" of a thumbnail uploaded in the same request, set by *File methods for ThumbnailFile
+	ThumbnailFile        *InputFile       `json:"-"`                   // Thumbnail uploaded by *File methods
+	Thumb                string           `json:"-"`                   // Deprecated: use Thumbnail
+	Caption              string           `json:"caption,omitempty"`
+	ParseMode            ParseMode        `json:"parse_mode,omitempty"`
+	CaptionEntities      []MessageEntity  `json:"caption_entities,omitempty"`
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // Send sticker optional params
 type SendStickerOptions struct {
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // SendVideoOptions video optional params SendVideo method
 type SendVideoOptions struct {
-	Duration            int         `json:"duration,omitempty"`
-	Width               int         `json:"width,omitempty"`
-	Height              int         `json:"height,omitempty"`
-	Thumb               string      `json:"thumb,omitempty"` // TODO add thumb as file
-	Caption             string      `json:"caption,omitempty"`
-	ParseMode           ParseMode   `json:"parse_mode,omitempty"`
-	SupportsStreaming   bool        `json:"supports_streaming,omitempty"`
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID  string           `json:"business_connection_id,omitempty"`
+	Duration              Seconds          `json:"duration,omitempty"`
+	Width                 int              `json:"width,omitempty"`
+	Height                int              `json:"height,omitempty"`
+	Thumbnail             string           `json:"thumbnail,omitempty"` // "attach://<file_attach_name>" of a thumbnail uploaded in the same request, set by *File methods for ThumbnailFile
+	ThumbnailFile         *InputFile       `json:"-"`                   // Thumbnail uploaded by *File methods
+	Thumb                 string           `json:"-"`                   // Deprecated: use Thumbnail
+	Caption               string           `json:"caption,omitempty"`
+	ParseMode             ParseMode        `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity  `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool             `json:"show_caption_above_media,omitempty"`
+	SupportsStreaming     bool             `json:"supports_streaming,omitempty"`
+	DisableNotification   bool             `json:"disable_notification,omitempty"`
+	ProtectContent        bool             `json:"protect_content,omitempty"`
+	MessageEffectID       string           `json:"message_effect_id,omitempty"`
+	ReplyParameters       *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID      int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup           ReplyMarkup      `json:"reply_markup,omitempty"`
+}
+
+// SendAnimationOptions optional params for SendAnimation method
+type SendAnimationOptions struct {
+	BusinessConnectionID  string           `json:"business_connection_id,omitempty"`
+	Duration              Seconds          `json:"duration,omitempty"`
+	Width                 int              `json:"width,omitempty"`
+	Height                int              `json:"height,omitempty"`
+	Thumbnail             string           `json:"thumbnail,omitempty"` // "attach://<file_attach_name>" of a thumbnail uploaded in the same request, set by *File methods for ThumbnailFile
+	ThumbnailFile         *InputFile       `json:"-"`                   // Thumbnail uploaded by *File methods
+	Thumb                 string           `json:"-"`                   // Deprecated: use Thumbnail
+	Caption               string           `json:"caption,omitempty"`
+	ParseMode             ParseMode        `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity  `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool             `json:"show_caption_above_media,omitempty"`
+	HasSpoiler            bool             `json:"has_spoiler,omitempty"`
+	DisableNotification   bool             `json:"disable_notification,omitempty"`
+	ProtectContent        bool             `json:"protect_content,omitempty"`
+	MessageEffectID       string           `json:"message_effect_id,omitempty"`
+	ReplyParameters       *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID      int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup           ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // SendVoiceOptions optional params for SendVoice method
 type SendVoiceOptions struct {
-	Caption             string      `json:"caption,omitempty"`
-	ParseMode           ParseMode   `json:"parse_mode,omitempty"`
-	Duration            int         `json:"duration,omitempty"`
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	Caption              string           `json:"caption,omitempty"`
+	ParseMode            ParseMode        `json:"parse_mode,omitempty"`
+	CaptionEntities      []MessageEntity  `json:"caption_entities,omitempty"`
+	Duration             Seconds          `json:"duration,omitempty"`
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // SendVideoNoteOptions optional params for SendVideoNote method
 type SendVideoNoteOptions struct {
-	Duration            int         `json:"duration,omitempty"`
-	Length              int         `json:"length,omitempty"`
-	Thumb               string      `json:"thumb,omitempty"` // TODO add thumb as file
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	Duration             Seconds          `json:"duration,omitempty"`
+	Length               int              `json:"length,omitempty"`
+	Thumbnail            string           `json:"thumbnail,omitempty"` // "attach://<file_attach_name>" of a thumbnail uploaded in the same request, set by *File methods for ThumbnailFile
+	ThumbnailFile        *InputFile       `json:"-"`                   // Thumbnail uploaded by *File methods
+	Thumb                string           `json:"-"`                   // Deprecated: use Thumbnail
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // SendLocationOptions optional params for SendLocation method
 type SendLocationOptions struct {
-	LivePeriod          int         `json:"live_period,omitempty"`
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	HorizontalAccuracy   float64          `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           Seconds          `json:"live_period,omitempty"`
+	Heading              int              `json:"heading,omitempty"`
+	ProximityAlertRadius int              `json:"proximity_alert_radius,omitempty"`
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
+}
+
+// EditMessageLiveLocationOptions optional params for EditMessageLiveLocation method
+type EditMessageLiveLocationOptions struct {
+	BusinessConnectionID string      `json:"business_connection_id,omitempty"`
+	LivePeriod           Seconds     `json:"live_period,omitempty"`
+	HorizontalAccuracy   float64     `json:"horizontal_accuracy,omitempty"`
+	Heading              int         `json:"heading,omitempty"`
+	ProximityAlertRadius int         `json:"proximity_alert_radius,omitempty"`
+	ReplyMarkup          ReplyMarkup `json:"reply_markup,omitempty"`
+}
+
+// StopMessageLiveLocationOptions optional params for StopMessageLiveLocation method
+type StopMessageLiveLocationOptions struct {
+	BusinessConnectionID string      `json:"business_connection_id,omitempty"`
+	ReplyMarkup          ReplyMarkup `json:"reply_markup,omitempty"`
 }
 
 // SendVenueOptions optional params for SendVenue method
 type SendVenueOptions struct {
-	FoursquareID        string      `json:"foursquare_id,omitempty"`
-	FoursquareType      string      `json:"foursquare_type,omitempty"`
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	FoursquareID         string           `json:"foursquare_id,omitempty"`
+	FoursquareType       string           `json:"foursquare_type,omitempty"`
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // SendContactOptions optional params for SendContact method
 type SendContactOptions struct {
-	VCard               string      `json:"vcard,omitempty"`
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	VCard                string           `json:"vcard,omitempty"`
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
 }
 
 // SendGameOptions optional params for SendGame method
 type SendGameOptions struct {
-	DisableNotification bool        `json:"disable_notification,omitempty"`
-	ReplyToMessageID    int64       `json:"reply_to_message_id,omitempty"`
-	ReplyMarkup         ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
+}
+
+// SendDiceOptions optional params for SendDice method
+type SendDiceOptions struct {
+	BusinessConnectionID string           `json:"business_connection_id,omitempty"`
+	DisableNotification  bool             `json:"disable_notification,omitempty"`
+	ProtectContent       bool             `json:"protect_content,omitempty"`
+	MessageEffectID      string           `json:"message_effect_id,omitempty"`
+	ReplyParameters      *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID     int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup          ReplyMarkup      `json:"reply_markup,omitempty"`
+}
+
+// SendPaidMediaOptions optional params for SendPaidMedia method
+type SendPaidMediaOptions struct {
+	BusinessConnectionID  string           `json:"business_connection_id,omitempty"`
+	Payload               string           `json:"payload,omitempty"`
+	Caption               string           `json:"caption,omitempty"`
+	ParseMode             ParseMode        `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity  `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool             `json:"show_caption_above_media,omitempty"`
+	DisableNotification   bool             `json:"disable_notification,omitempty"`
+	ProtectContent        bool             `json:"protect_content,omitempty"`
+	ReplyParameters       *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID      int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup           ReplyMarkup      `json:"reply_markup,omitempty"`
+}
+
+// SendPollOptions optional params for SendPoll method
+type SendPollOptions struct {
+	BusinessConnectionID  string           `json:"business_connection_id,omitempty"`
+	QuestionParseMode     ParseMode        `json:"question_parse_mode,omitempty"`
+	QuestionEntities      []MessageEntity  `json:"question_entities,omitempty"`
+	IsAnonymous           *bool            `json:"is_anonymous,omitempty"` // Defaults to true
+	Type                  PollType         `json:"type,omitempty"`
+	AllowsMultipleAnswers bool             `json:"allows_multiple_answers,omitempty"`
+	CorrectOptionID       *int             `json:"correct_option_id,omitempty"` // Required for quiz mode
+	Explanation           string           `json:"explanation,omitempty"`
+	ExplanationParseMode  ParseMode        `json:"explanation_parse_mode,omitempty"`
+	ExplanationEntities   []MessageEntity  `json:"explanation_entities,omitempty"`
+	OpenPeriod            Seconds          `json:"open_period,omitempty"`
+	CloseDate             UnixTime         `json:"close_date,omitempty"`
+	IsClosed              bool             `json:"is_closed,omitempty"`
+	DisableNotification   bool             `json:"disable_notification,omitempty"`
+	ProtectContent        bool             `json:"protect_content,omitempty"`
+	MessageEffectID       string           `json:"message_effect_id,omitempty"`
+	ReplyParameters       *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID      int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup           ReplyMarkup      `json:"reply_markup,omitempty"`
+}
+
+// CopyMessageOptions optional params for CopyMessage method
+type CopyMessageOptions struct {
+	Caption               string           `json:"caption,omitempty"`
+	ParseMode             ParseMode        `json:"parse_mode,omitempty"`
+	ShowCaptionAboveMedia bool             `json:"show_caption_above_media,omitempty"`
+	CaptionEntities       []MessageEntity  `json:"caption_entities,omitempty"`
+	DisableNotification   bool             `json:"disable_notification,omitempty"`
+	ProtectContent        bool             `json:"protect_content,omitempty"`
+	ReplyParameters       *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyToMessageID      int64            `json:"reply_to_message_id,omitempty"` // Deprecated: use ReplyParameters
+	ReplyMarkup           ReplyMarkup      `json:"reply_markup,omitempty"`
+}
+
+// ForwardMessagesOptions optional params for ForwardMessages method
+type ForwardMessagesOptions struct {
+	DisableNotification bool `json:"disable_notification,omitempty"`
+	ProtectContent      bool `json:"protect_content,omitempty"`
+}
+
+// CopyMessagesOptions optional params for CopyMessages method
+type CopyMessagesOptions struct {
+	DisableNotification bool `json:"disable_notification,omitempty"`
+	ProtectContent      bool `json:"protect_content,omitempty"`
+	RemoveCaption       bool `json:"remove_caption,omitempty"`
 }
 
 // Set game score optional params
@@ -193,34 +382,53 @@ type GetGameHighScoresOptions struct {
 
 // Edit message text optional params
 type EditMessageTextOptions struct {
-	ParseMode             ParseMode   `json:"parse_mode,omitempty"`
-	DisableWebPagePreview bool        `json:"disable_web_page_preview,omitempty"`
-	ReplyMarkup           ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID  string              `json:"business_connection_id,omitempty"`
+	ParseMode             ParseMode           `json:"parse_mode,omitempty"`
+	Entities              []MessageEntity     `json:"entities,omitempty"`
+	LinkPreviewOptions    *LinkPreviewOptions `json:"link_preview_options,omitempty"`
+	DisableWebPagePreview bool                `json:"disable_web_page_preview,omitempty"` // Deprecated: use LinkPreviewOptions
+	ReplyMarkup           ReplyMarkup         `json:"reply_markup,omitempty"`
 }
 
 // Edit message caption optional params
 type EditMessageCationOptions struct {
-	Caption     string      `json:"caption,omitempty"`
-	ReplyMarkup ReplyMarkup `json:"reply_markup,omitempty"`
+	BusinessConnectionID  string          `json:"business_connection_id,omitempty"`
+	Caption               string          `json:"caption,omitempty"`
+	ParseMode             ParseMode       `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool            `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           ReplyMarkup     `json:"reply_markup,omitempty"`
 }
 
 // Answer callback query optional params
 type AnswerCallbackQueryOptions struct {
-	Text      string `json:"text,omitempty"`
-	ShowAlert bool   `json:"show_alert,omitempty"`
-	URL       string `json:"url,omitempty"`
-	CacheTime int    `json:"cache_time,omitempty"`
+	Text      string  `json:"text,omitempty"`
+	ShowAlert bool    `json:"show_alert,omitempty"`
+	URL       string  `json:"url,omitempty"`
+	CacheTime Seconds `json:"cache_time,omitempty"`
 }
 
 // Answer inline query optional params
 type AnswerInlineQueryOptions struct {
-	CacheTime         int    `json:"cache_time,omitempty"`
-	IsPersonal        bool   `json:"is_personal,omitempty"`
-	NextOffset        string `json:"next_offset,omitempty"`
-	SwitchPmText      string `json:"switch_pm_text,omitempty"`
+	CacheTime  Seconds                   `json:"cache_time,omitempty"`
+	IsPersonal bool                      `json:"is_personal,omitempty"`
+	NextOffset string                    `json:"next_offset,omitempty"`
+	Button     *InlineQueryResultsButton `json:"button,omitempty"`
+
+	// Deprecated: use Button
+	SwitchPmText string `json:"switch_pm_text,omitempty"`
+	// Deprecated: use Button
 	SwitchPmParameter string `json:"switch_pm_parameter,omitempty"`
 }
 
+// SavePreparedInlineMessage optional params
+type SavePreparedInlineMessageOptions struct {
+	AllowUserChats    bool `json:"allow_user_chats,omitempty"`
+	AllowBotChats     bool `json:"allow_bot_chats,omitempty"`
+	AllowGroupChats   bool `json:"allow_group_chats,omitempty"`
+	AllowChannelChats bool `json:"allow_channel_chats,omitempty"`
+}
+
 // Set webhook query optional params
 type SetWebhookOptions struct {
 	Certificate    []byte   `json:"certificate,omitempty"`
@@ -0,0 +1,185 @@
+package micha
+
+import (
+	"sync"
+)
+
+type trackedPoll struct {
+	poll    Poll
+	answers map[int64][]int
+}
+
+// PollTracker aggregates answers of non-anonymous polls.
+// Register polls sent by the bot with Track and feed the tracker with updates (poll and poll_answer),
+// answers to polls which are not registered are ignored.
+type PollTracker struct {
+	mu    sync.RWMutex
+	polls map[string]*trackedPoll
+}
+
+// NewPollTracker - create new poll tracker
+func NewPollTracker() *PollTracker {
+	return &PollTracker{
+		polls: map[string]*trackedPoll{},
+	}
+}
+
+// Track - register poll, e.g. Message.Poll returned by SendPoll
+func (t *PollTracker) Track(poll Poll) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.polls[poll.ID]
+	if !ok {
+		p = &trackedPoll{answers: map[int64][]int{}}
+		t.polls[poll.ID] = p
+	}
+	correctOptionID := p.poll.CorrectOptionID
+	p.poll = poll
+	if p.poll.CorrectOptionID == nil {
+		// Poll updates don't contain correct option until poll is closed
+		p.poll.CorrectOptionID = correctOptionID
+	}
+}
+
+// HandleUpdate - process poll and poll_answer updates.
+// Returns true if update was consumed by the tracker.
+func (t *PollTracker) HandleUpdate(update Update) bool {
+	switch {
+	case update.Poll != nil:
+		t.Track(*update.Poll)
+		return true
+	case update.PollAnswer != nil:
+		return t.Answer(*update.PollAnswer)
+	}
+
+	return false
+}
+
+// Answer - register poll answer, empty OptionIDs retracts the vote.
+// Answers to polls which are not tracked are ignored, returns false for them.
+func (t *PollTracker) Answer(answer PollAnswer) bool {
+	voterID := int64(0)
+	switch {
+	case answer.User != nil:
+		voterID = answer.User.ID
+	case answer.VoterChat != nil:
+		// Chat ids are negative so they don't collide with user ids
+		id, ok := answer.VoterChat.ID.Int64()
+		if !ok {
+			return false
+		}
+		voterID = id
+	default:
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.polls[answer.PollID]
+	if !ok {
+		return false
+	}
+	if len(answer.OptionIDs) == 0 {
+		delete(p.answers, voterID)
+		return true
+	}
+
+	p.answers[voterID] = append([]int(nil), answer.OptionIDs...)
+
+	return true
+}
+
+// Answers - return chosen options by user id
+func (t *PollTracker) Answers(pollID string) map[int64][]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	answers := map[int64][]int{}
+	p, ok := t.polls[pollID]
+	if !ok {
+		return answers
+	}
+
+	for userID, optionIDs := range p.answers {
+		answers[userID] = append([]int(nil), optionIDs...)
+	}
+
+	return answers
+}
+
+// Counts - return number of votes per option
+func (t *PollTracker) Counts(pollID string) []int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	p, ok := t.polls[pollID]
+	if !ok {
+		return nil
+	}
+
+	counts := make([]int, len(p.poll.Options))
+	for _, optionIDs := range p.answers {
+		for _, id := range optionIDs {
+			if id < 0 {
+				continue
+			}
+			if id >= len(counts) {
+				counts = append(counts, make([]int, id-len(counts)+1)...)
+			}
+			counts[id]++
+		}
+	}
+
+	return counts
+}
+
+// Score - return number of quizzes answered correctly by user
+func (t *PollTracker) Score(userID int64) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	score := 0
+	for _, p := range t.polls {
+		if p.poll.Type != POLL_TYPE_QUIZ || p.poll.CorrectOptionID == nil {
+			continue
+		}
+
+		optionIDs := p.answers[userID]
+		if len(optionIDs) == 1 && optionIDs[0] == *p.poll.CorrectOptionID {
+			score++
+		}
+	}
+
+	return score
+}
+
+// Scores - return quiz scores of all users
+func (t *PollTracker) Scores() map[int64]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	scores := map[int64]int{}
+	for _, p := range t.polls {
+		if p.poll.Type != POLL_TYPE_QUIZ || p.poll.CorrectOptionID == nil {
+			continue
+		}
+
+		for userID, optionIDs := range p.answers {
+			if len(optionIDs) == 1 && optionIDs[0] == *p.poll.CorrectOptionID {
+				scores[userID]++
+			}
+		}
+	}
+
+	return scores
+}
+
+// Forget - remove poll from tracker
+func (t *PollTracker) Forget(pollID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.polls, pollID)
+}
@@ -0,0 +1,48 @@
+package micha
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollTracker(t *testing.T) {
+	correctOptionID := 1
+	tracker := NewPollTracker()
+	tracker.Track(Poll{
+		ID:              "q1",
+		Type:            POLL_TYPE_QUIZ,
+		Options:         []PollOption{{Text: "a"}, {Text: "b"}},
+		CorrectOptionID: &correctOptionID,
+	})
+
+	updates := []string{
+		`{"update_id":1,"poll_answer":{"poll_id":"q1","user":{"id":10},"option_ids":[1]}}`,
+		`{"update_id":2,"poll_answer":{"poll_id":"q1","user":{"id":20},"option_ids":[0]}}`,
+		`{"update_id":3,"poll_answer":{"poll_id":"q1","user":{"id":30},"option_ids":[1]}}`,
+		`{"update_id":4,"poll_answer":{"poll_id":"q1","user":{"id":30},"option_ids":[]}}`,
+		`{"update_id":5,"poll":{"id":"q1","type":"quiz","options":[{"text":"a","voter_count":1},{"text":"b","voter_count":1}]}}`,
+		`{"update_id":6,"message":{"message_id":1}}`,
+	}
+	for i, data := range updates {
+		update := Update{}
+		require.Nil(t, json.Unmarshal([]byte(data), &update))
+		require.Equal(t, i < 5, tracker.HandleUpdate(update))
+	}
+
+	require.Equal(t, []int{1, 1}, tracker.Counts("q1"))
+	require.Equal(t, map[int64][]int{10: {1}, 20: {0}}, tracker.Answers("q1"))
+	require.Equal(t, 1, tracker.Score(10))
+	require.Equal(t, 0, tracker.Score(20))
+	require.Equal(t, map[int64]int{10: 1}, tracker.Scores())
+
+	// Answers to polls which are not tracked are ignored
+	require.False(t, tracker.Answer(PollAnswer{PollID: "unknown", User: &User{ID: 10}, OptionIDs: []int{0}}))
+	require.Len(t, tracker.polls, 1)
+	require.Empty(t, tracker.Answers("unknown"))
+
+	tracker.Forget("q1")
+	require.Nil(t, tracker.Counts("q1"))
+	require.Equal(t, 0, tracker.Score(10))
+}
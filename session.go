@@ -0,0 +1,454 @@
+package micha
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSessionVersionConflict = errors.New("session was modified concurrently")
+	ErrNoSessionKey           = errors.New("update has no session key")
+)
+
+// Session - data kept between updates of a user or chat
+type Session struct {
+	Key       string                     `json:"key"`
+	Version   uint64                     `json:"version"` // Incremented by store on every save
+	Values    map[string]json.RawMessage `json:"values,omitempty"`
+	ExpiresAt time.Time                  `json:"expires_at,omitempty"` // Zero means the session never expires
+}
+
+// NewSession - create new empty session
+func NewSession(key string) *Session {
+	return &Session{
+		Key:    key,
+		Values: map[string]json.RawMessage{},
+	}
+}
+
+// Get - decode value into target, returns false if there is no value
+func (s *Session) Get(name string, target interface{}) (bool, error) {
+	data, ok := s.Values[name]
+	if !ok {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(data, target)
+}
+
+// Set - store value
+func (s *Session) Set(name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if s.Values == nil {
+		s.Values = map[string]json.RawMessage{}
+	}
+	s.Values[name] = data
+
+	return nil
+}
+
+// Delete - remove value
+func (s *Session) Delete(name string) {
+	delete(s.Values, name)
+}
+
+func (s *Session) expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
+func (s *Session) copy() *Session {
+	session := *s
+	session.Values = make(map[string]json.RawMessage, len(s.Values))
+	for k, v := range s.Values {
+		session.Values[k] = append(json.RawMessage(nil), v...)
+	}
+
+	return &session
+}
+
+// SessionStore persists sessions.
+//
+// Get returns nil session without error if there is no session or it is expired.
+// Set saves session only if its Version matches the stored one (0 for new sessions),
+// otherwise ErrSessionVersionConflict is returned.
+// On success Version is incremented and ExpiresAt is updated according to ttl (zero ttl disables expiration).
+type SessionStore interface {
+	Get(key string) (*Session, error)
+	Set(session *Session, ttl time.Duration) error
+	Delete(key string) error
+}
+
+// Stores purge expired sessions on Set at most once per interval
+const sessionPurgeInterval = time.Minute
+
+// MemorySessionStore - in-memory SessionStore.
+// Expired sessions are removed on Get and purged periodically on Set.
+type MemorySessionStore struct {
+	mu        sync.Mutex
+	sessions  map[string]*Session
+	now       func() time.Time
+	lastPurge time.Time
+}
+
+// NewMemorySessionStore - create new in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: map[string]*Session{},
+		now:      time.Now,
+	}
+}
+
+func (s *MemorySessionStore) Get(key string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[key]
+	if !ok {
+		return nil, nil
+	}
+	if session.expired(s.now()) {
+		delete(s.sessions, key)
+		return nil, nil
+	}
+
+	return session.copy(), nil
+}
+
+func (s *MemorySessionStore) Set(session *Session, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version := uint64(0)
+	if stored, ok := s.sessions[session.Key]; ok && !stored.expired(s.now()) {
+		version = stored.Version
+	}
+	if session.Version != version {
+		return ErrSessionVersionConflict
+	}
+
+	session.Version++
+	session.ExpiresAt = sessionExpiresAt(s.now(), ttl)
+	s.sessions[session.Key] = session.copy()
+
+	if s.now().Sub(s.lastPurge) >= sessionPurgeInterval {
+		s.purge()
+	}
+
+	return nil
+}
+
+// Purge - remove expired sessions, returns number of removed sessions
+func (s *MemorySessionStore) Purge() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.purge()
+}
+
+func (s *MemorySessionStore) purge() int {
+	now := s.now()
+	s.lastPurge = now
+
+	removed := 0
+	for key, session := range s.sessions {
+		if session.expired(now) {
+			delete(s.sessions, key)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+func (s *MemorySessionStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, key)
+	return nil
+}
+
+// FileSessionStore - SessionStore keeping every session in a JSON file inside directory.
+// Version checks are safe within one process only.
+// Expired sessions are removed on Get and purged periodically on Set.
+type FileSessionStore struct {
+	mu        sync.Mutex
+	dir       string
+	now       func() time.Time
+	lastPurge time.Time
+}
+
+// NewFileSessionStore - create new file session store, directory is created if not exists
+func NewFileSessionStore(dir string) (*FileSessionStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileSessionStore{
+		dir: dir,
+		now: time.Now,
+	}, nil
+}
+
+func (s *FileSessionStore) path(key string) string {
+	return filepath.Join(s.dir, base64.RawURLEncoding.EncodeToString([]byte(key))+".json")
+}
+
+func (s *FileSessionStore) load(key string) (*Session, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session := &Session{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, fmt.Errorf("decode session %q: %w", key, err)
+	}
+
+	if session.expired(s.now()) {
+		return nil, os.Remove(s.path(key))
+	}
+
+	return session, nil
+}
+
+func (s *FileSessionStore) Get(key string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(key)
+}
+
+func (s *FileSessionStore) Set(session *Session, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.load(session.Key)
+	if err != nil {
+		return err
+	}
+
+	version := uint64(0)
+	if stored != nil {
+		version = stored.Version
+	}
+	if session.Version != version {
+		return ErrSessionVersionConflict
+	}
+
+	saved := session.copy()
+	saved.Version++
+	saved.ExpiresAt = sessionExpiresAt(s.now(), ttl)
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	// Write to temporary file first so session is never stored partially
+	tmp, err := os.CreateTemp(s.dir, ".session-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path(session.Key)); err != nil {
+		return err
+	}
+
+	session.Version = saved.Version
+	session.ExpiresAt = saved.ExpiresAt
+
+	if s.now().Sub(s.lastPurge) >= sessionPurgeInterval {
+		if _, err := s.purge(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Purge - remove files of expired sessions, returns number of removed sessions.
+// Files which can't be decoded are left untouched.
+func (s *FileSessionStore) Purge() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.purge()
+}
+
+func (s *FileSessionStore) purge() (int, error) {
+	now := s.now()
+	s.lastPurge = now
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+
+		session := &Session{}
+		if json.Unmarshal(data, session) != nil || !session.expired(now) {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+func (s *FileSessionStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func sessionExpiresAt(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(ttl)
+}
+
+// SessionKeyFunc - build session key for update, returns false if update has no key
+type SessionKeyFunc func(update Update) (string, bool)
+
+// SessionKeyUser - one session per user.
+// Anonymous group administrators and channels share the session of the chat they send on behalf of.
+func SessionKeyUser(update Update) (string, bool) {
+	sender := update.EffectiveSender()
+	switch {
+	case sender == nil:
+		return "", false
+	case sender.Chat != nil:
+		// User of such messages is a placeholder shared by all anonymous administrators
+		return fmt.Sprintf("sender_chat:%s", sender.Chat.ID), true
+	}
+
+	return fmt.Sprintf("user:%d", sender.User.ID), true
+}
+
+// SessionKeyChat - one session per chat
+func SessionKeyChat(update Update) (string, bool) {
+	chat := update.EffectiveChat()
+	if chat == nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("chat:%s", chat.ID), true
+}
+
+// SessionKeyUserInChat - one session per user in every chat, senders on behalf of chats are keyed like in SessionKeyUser
+func SessionKeyUserInChat(update Update) (string, bool) {
+	chat := update.EffectiveChat()
+	if chat == nil {
+		return "", false
+	}
+
+	sender, ok := SessionKeyUser(update)
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf("chat:%s:%s", chat.ID, sender), true
+}
+
+// SessionKeyTopic - one session per forum topic, messages outside topics share the chat session
+func SessionKeyTopic(update Update) (string, bool) {
+	chat := update.EffectiveChat()
+	if chat == nil {
+		return "", false
+	}
+
+	message := update.EffectiveMessage()
+	if message == nil || !message.IsTopicMessage {
+		return fmt.Sprintf("chat:%s:topic:0", chat.ID), true
+	}
+
+	return fmt.Sprintf("chat:%s:topic:%d", chat.ID, message.MessageThreadID), true
+}
+
+// SessionHandler - update handler with session
+type SessionHandler func(update Update, session *Session) error
+
+// SessionMiddleware loads session of every update before handler and saves it afterwards
+type SessionMiddleware struct {
+	store SessionStore
+	key   SessionKeyFunc
+	ttl   time.Duration
+}
+
+// NewSessionMiddleware - create session middleware, zero ttl disables sessions expiration
+func NewSessionMiddleware(store SessionStore, key SessionKeyFunc, ttl time.Duration) *SessionMiddleware {
+	return &SessionMiddleware{
+		store: store,
+		key:   key,
+		ttl:   ttl,
+	}
+}
+
+// Wrap - wrap handler. Session is not saved if handler returns error,
+// ErrSessionVersionConflict is returned if session was saved by other handler in the meantime.
+// Updates without session key are passed to handler with nil session.
+func (m *SessionMiddleware) Wrap(handler SessionHandler) func(update Update) error {
+	return func(update Update) error {
+		key, ok := m.key(update)
+		if !ok {
+			return handler(update, nil)
+		}
+
+		session, err := m.store.Get(key)
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			session = NewSession(key)
+		}
+
+		if err := handler(update, session); err != nil {
+			return err
+		}
+
+		return m.store.Set(session, m.ttl)
+	}
+}
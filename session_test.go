@@ -0,0 +1,191 @@
+package micha
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSessionStore(t *testing.T, store SessionStore, now *time.Time) {
+	session, err := store.Get("key")
+	require.Nil(t, err)
+	require.Nil(t, session)
+
+	session = NewSession("key")
+	require.Nil(t, session.Set("count", 1))
+	require.Nil(t, store.Set(session, time.Minute))
+	require.Equal(t, uint64(1), session.Version)
+	require.Equal(t, now.Add(time.Minute), session.ExpiresAt)
+
+	// Concurrent modification
+	stale, err := store.Get("key")
+	require.Nil(t, err)
+	fresh, err := store.Get("key")
+	require.Nil(t, err)
+	require.Nil(t, fresh.Set("count", 2))
+	require.Nil(t, store.Set(fresh, 0))
+	require.ErrorIs(t, store.Set(stale, 0), ErrSessionVersionConflict)
+	require.ErrorIs(t, store.Set(NewSession("key"), 0), ErrSessionVersionConflict)
+
+	session, err = store.Get("key")
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), session.Version)
+	require.True(t, session.ExpiresAt.IsZero())
+	count := 0
+	ok, err := session.Get("count", &count)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, count)
+
+	// Expiration
+	require.Nil(t, store.Set(session, time.Second))
+	*now = now.Add(time.Second)
+	session, err = store.Get("key")
+	require.Nil(t, err)
+	require.Nil(t, session)
+	require.Nil(t, store.Set(NewSession("key"), 0))
+
+	require.Nil(t, store.Delete("key"))
+	require.Nil(t, store.Delete("key"))
+	session, err = store.Get("key")
+	require.Nil(t, err)
+	require.Nil(t, session)
+}
+
+func TestMemorySessionStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemorySessionStore()
+	store.now = func() time.Time { return now }
+
+	testSessionStore(t, store, &now)
+}
+
+func TestFileSessionStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store, err := NewFileSessionStore(t.TempDir())
+	require.Nil(t, err)
+	store.now = func() time.Time { return now }
+
+	testSessionStore(t, store, &now)
+}
+
+func TestMemorySessionStorePurge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemorySessionStore()
+	store.now = func() time.Time { return now }
+
+	require.Nil(t, store.Set(NewSession("short"), time.Second))
+	require.Nil(t, store.Set(NewSession("long"), time.Hour))
+	require.Nil(t, store.Set(NewSession("forever"), 0))
+
+	now = now.Add(2 * time.Second)
+	require.Equal(t, 1, store.Purge())
+	require.Len(t, store.sessions, 2)
+
+	// Expired sessions are purged on Set once per interval
+	require.Nil(t, store.Set(NewSession("short"), time.Second))
+	now = now.Add(2 * time.Second)
+	require.Nil(t, store.Set(NewSession("other"), 0))
+	require.Len(t, store.sessions, 4)
+
+	now = now.Add(sessionPurgeInterval)
+	require.Nil(t, store.Set(NewSession("another"), 0))
+	require.Len(t, store.sessions, 4)
+	require.NotContains(t, store.sessions, "short")
+}
+
+func TestFileSessionStorePurge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	store, err := NewFileSessionStore(dir)
+	require.Nil(t, err)
+	store.now = func() time.Time { return now }
+
+	require.Nil(t, store.Set(NewSession("short"), time.Second))
+	require.Nil(t, store.Set(NewSession("long"), time.Hour))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600))
+
+	now = now.Add(2 * time.Second)
+	removed, err := store.Purge()
+	require.Nil(t, err)
+	require.Equal(t, 1, removed)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{filepath.Join(dir, "broken.json"), store.path("long")}, paths)
+
+	require.Nil(t, store.Set(NewSession("short"), time.Second))
+	now = now.Add(sessionPurgeInterval)
+	require.Nil(t, store.Set(NewSession("other"), 0))
+	_, err = os.Stat(store.path("short"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestSessionKeys(t *testing.T) {
+	update := Update{Message: &Message{
+		Chat:            Chat{ID: "-100"},
+		From:            &User{ID: 1},
+		MessageThreadID: 5,
+		IsTopicMessage:  true,
+	}}
+
+	for _, c := range []struct {
+		keyFunc SessionKeyFunc
+		key     string
+	}{
+		{SessionKeyUser, "user:1"},
+		{SessionKeyChat, "chat:-100"},
+		{SessionKeyUserInChat, "chat:-100:user:1"},
+		{SessionKeyTopic, "chat:-100:topic:5"},
+	} {
+		key, ok := c.keyFunc(update)
+		require.True(t, ok)
+		require.Equal(t, c.key, key)
+	}
+
+	_, ok := SessionKeyChat(Update{InlineQuery: &InlineQuery{}})
+	require.False(t, ok)
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	store := NewMemorySessionStore()
+	middleware := NewSessionMiddleware(store, SessionKeyUser, time.Hour)
+
+	handler := middleware.Wrap(func(update Update, session *Session) error {
+		if update.Message.Text == "fail" {
+			require.Nil(t, session.Set("count", 100))
+			return errors.New("fail")
+		}
+
+		count := 0
+		_, err := session.Get("count", &count)
+		require.Nil(t, err)
+		return session.Set("count", count+1)
+	})
+
+	update := Update{Message: &Message{From: &User{ID: 1}}}
+	require.Nil(t, handler(update))
+	require.Nil(t, handler(update))
+	require.NotNil(t, handler(Update{Message: &Message{From: &User{ID: 1}, Text: "fail"}}))
+
+	session, err := store.Get("user:1")
+	require.Nil(t, err)
+	count := 0
+	_, err = session.Get("count", &count)
+	require.Nil(t, err)
+	require.Equal(t, 2, count)
+	require.Equal(t, uint64(2), session.Version)
+
+	called := false
+	err = middleware.Wrap(func(update Update, session *Session) error {
+		called = true
+		require.Nil(t, session)
+		return nil
+	})(Update{Poll: &Poll{}})
+	require.Nil(t, err)
+	require.True(t, called)
+}
@@ -0,0 +1,156 @@
+package micha
+
+import (
+	"errors"
+	"unicode/utf16"
+)
+
+const (
+	// MaxMessageLength - max text length of a message in UTF-16 code units
+	MaxMessageLength = 4096
+	// MaxCaptionLength - max caption length of a media message in UTF-16 code units
+	MaxCaptionLength = 1024
+)
+
+var (
+	ErrSplitParseMode = errors.New("splitting is supported only for plain text, entities, HTML and MarkdownV2")
+	ErrEmptyText      = errors.New("text is empty")
+)
+
+// TextPart is a chunk of the split text with its own entities
+type TextPart struct {
+	Text     string
+	Entities []MessageEntity
+}
+
+// SplitText - split text into parts of at most limit UTF-16 code units.
+// Text is cut at paragraph, line or word boundaries, preferring cuts outside of entities.
+// Surrogate pairs are never broken, so with limit 1 a part may contain 2 code units.
+// Entities that can't be kept in one part are split and re-applied in each part.
+func SplitText(text string, entities []MessageEntity, limit int) []TextPart {
+	units := toUTF16(text)
+	if limit <= 0 {
+		limit = MaxMessageLength
+	}
+
+	parts := []TextPart{}
+	for start := 0; start < len(units); {
+		if len(units)-start <= limit {
+			parts = append(parts, newTextPart(units, entities, start, len(units)))
+			break
+		}
+
+		end, next := findCut(units, entities, start, start+limit)
+		parts = append(parts, newTextPart(units, entities, start, end))
+		start = next
+	}
+
+	return parts
+}
+
+// SplitCaption - split caption into a part of at most MaxCaptionLength code units
+// and the rest split into parts of at most MaxMessageLength (see SplitText)
+func SplitCaption(text string, entities []MessageEntity) (TextPart, []TextPart) {
+	units := toUTF16(text)
+	if len(units) <= MaxCaptionLength {
+		return newTextPart(units, entities, 0, len(units)), nil
+	}
+
+	end, next := findCut(units, entities, 0, MaxCaptionLength)
+	rest := newTextPart(units, entities, next, len(units))
+
+	return newTextPart(units, entities, 0, end), SplitText(rest.Text, rest.Entities, MaxMessageLength)
+}
+
+// parseSplitText - convert text in parse mode to plain text and entities for splitting
+func parseSplitText(text string, parseMode ParseMode, entities []MessageEntity) (string, []MessageEntity, error) {
+	switch parseMode {
+	case PARSE_MODE_DEFAULT:
+		return text, entities, nil
+	case PARSE_MODE_HTML:
+		return ParseHTML(text)
+	case PARSE_MODE_MARKDOWN_V2:
+		return ParseMarkdownV2(text)
+	}
+
+	return "", nil, ErrSplitParseMode
+}
+
+func newTextPart(units []uint16, entities []MessageEntity, start, end int) TextPart {
+	part := TextPart{
+		Text: fromUTF16(units[start:end]),
+	}
+	for _, entity := range entities {
+		from, to := max(entity.Offset, start), min(entity.End(), end)
+		if from >= to {
+			continue
+		}
+		entity.Offset, entity.Length = from-start, to-from
+		part.Entities = append(part.Entities, entity)
+	}
+
+	return part
+}
+
+// findCut - return end of the current part and start of the next one
+func findCut(units []uint16, entities []MessageEntity, start, limit int) (int, int) {
+	separators := [][]uint16{{'\n', '\n'}, {'\n'}, {' '}}
+
+	cut := func(strict bool) (int, int, bool) {
+		for _, separator := range separators {
+			for end := limit - len(separator); end > start; end-- {
+				if !hasSeparator(units, end, separator) {
+					continue
+				}
+				if strict && splitsEntity(entities, end, end+len(separator)) {
+					continue
+				}
+				return end, end + len(separator), true
+			}
+		}
+		return 0, 0, false
+	}
+
+	if end, next, ok := cut(true); ok {
+		return end, next
+	}
+	if end, next, ok := cut(false); ok {
+		return end, next
+	}
+
+	// Hard cut, don't break surrogate pair
+	end := limit
+	if utf16.IsSurrogate(rune(units[end-1])) && units[end-1] < 0xdc00 {
+		end--
+	}
+	if end <= start {
+		// Limit of one code unit, take the whole pair so the split makes progress
+		end = start + 2
+	}
+
+	return end, end
+}
+
+func hasSeparator(units []uint16, position int, separator []uint16) bool {
+	if position+len(separator) > len(units) {
+		return false
+	}
+	for i := range separator {
+		if units[position+i] != separator[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitsEntity - check that cutting out [from, to) would split some entity
+func splitsEntity(entities []MessageEntity, from, to int) bool {
+	for _, entity := range entities {
+		if entity.Offset < from && entity.End() > to {
+			return true
+		}
+	}
+
+	return false
+}
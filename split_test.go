@@ -0,0 +1,86 @@
+package micha
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitText(t *testing.T) {
+	// Fits into one part
+	parts := SplitText("short", nil, 10)
+	require.Equal(t, []TextPart{{Text: "short"}}, parts)
+
+	// Paragraph boundary is preferred
+	parts = SplitText("aaaa\n\nbb cc\ndd", nil, 10)
+	require.Equal(t, []TextPart{{Text: "aaaa"}, {Text: "bb cc\ndd"}}, parts)
+
+	// Word boundary, entity is not broken
+	text := "one two three"
+	entities := []MessageEntity{{Type: MESSAGE_ENTITY_BOLD, Offset: 4, Length: 9}}
+	parts = SplitText(text, entities, 10)
+	require.Equal(t, []TextPart{
+		{Text: "one"},
+		{Text: "two three", Entities: []MessageEntity{{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 9}}},
+	}, parts)
+
+	// Entity longer than limit is split and re-applied
+	text = "aaaa bbbb cccc"
+	entities = []MessageEntity{{Type: MESSAGE_ENTITY_ITALIC, Offset: 0, Length: 14}}
+	parts = SplitText(text, entities, 10)
+	require.Equal(t, []TextPart{
+		{Text: "aaaa bbbb", Entities: []MessageEntity{{Type: MESSAGE_ENTITY_ITALIC, Offset: 0, Length: 9}}},
+		{Text: "cccc", Entities: []MessageEntity{{Type: MESSAGE_ENTITY_ITALIC, Offset: 0, Length: 4}}},
+	}, parts)
+
+	// Hard cut doesn't break surrogate pairs
+	parts = SplitText(strings.Repeat("😀", 3), nil, 3)
+	require.Equal(t, []TextPart{{Text: "😀"}, {Text: "😀"}, {Text: "😀"}}, parts)
+
+	// Surrogate pair is kept whole even if it doesn't fit the limit
+	parts = SplitText("😀a😀", nil, 1)
+	require.Equal(t, []TextPart{{Text: "😀"}, {Text: "a"}, {Text: "😀"}}, parts)
+}
+
+func TestSplitCaption(t *testing.T) {
+	caption, rest := SplitCaption("short", nil)
+	require.Equal(t, TextPart{Text: "short"}, caption)
+	require.Nil(t, rest)
+
+	long := strings.Repeat("a", 1000)
+	text := long + " " + long + "\n\n" + strings.Repeat("b", 5000)
+	entities := []MessageEntity{{Type: MESSAGE_ENTITY_BOLD, Offset: 990, Length: 20}}
+	caption, rest = SplitCaption(text, entities)
+	require.Equal(t, TextPart{Text: long, Entities: []MessageEntity{{Type: MESSAGE_ENTITY_BOLD, Offset: 990, Length: 10}}}, caption)
+	require.Equal(t, 3, len(rest))
+	require.Equal(t, long, rest[0].Text)
+	require.Equal(t, []MessageEntity{{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 9}}, rest[0].Entities)
+	require.Equal(t, MaxMessageLength, len(rest[1].Text))
+}
+
+func TestParseHTML(t *testing.T) {
+	text, entities, err := ParseHTML(`<b>bold <i>it&amp;al</i></b> <a href="https://x.com/?a=1&amp;b=2">link</a> <a href='tg://user?id=42'>user</a>` +
+		`<pre><code class="language-go">x &lt; 1</code></pre><span class="tg-spoiler">s</span><blockquote expandable>q</blockquote> & 😀<tg-emoji emoji-id="5">👍</tg-emoji>`)
+	require.Nil(t, err)
+	require.Equal(t, "bold it&al link userx < 1sq & 😀👍", text)
+	require.Equal(t, []MessageEntity{
+		{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 10},
+		{Type: MESSAGE_ENTITY_ITALIC, Offset: 5, Length: 5},
+		{Type: MESSAGE_ENTITY_TEXT_LINK, Offset: 11, Length: 4, URL: "https://x.com/?a=1&b=2"},
+		{Type: MESSAGE_ENTITY_TEXT_MENTION, Offset: 16, Length: 4, User: &User{ID: 42}},
+		{Type: MESSAGE_ENTITY_PRE, Offset: 20, Length: 5, Language: "go"},
+		{Type: MESSAGE_ENTITY_SPOILER, Offset: 25, Length: 1},
+		{Type: MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE, Offset: 26, Length: 1},
+		{Type: MESSAGE_ENTITY_CUSTOM_EMOJI, Offset: 32, Length: 2, CustomEmojiID: "5"},
+	}, entities)
+
+	_, _, err = ParseHTML("<b>bold")
+	require.NotNil(t, err)
+	_, _, err = ParseHTML("<b>bold</i>")
+	require.NotNil(t, err)
+	_, _, err = ParseHTML("<div>x</div>")
+	require.NotNil(t, err)
+	_, _, err = ParseHTML("<b")
+	require.NotNil(t, err)
+}
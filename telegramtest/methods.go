@@ -0,0 +1,475 @@
+package telegramtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onrik/micha"
+)
+
+// maxPollTimeout - getUpdates timeout cap, keeps tests from hanging on the default 25 seconds
+const maxPollTimeout = 5 * time.Second
+
+type apiError struct {
+	code        int
+	description string
+}
+
+func badRequest(format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusBadRequest, "Bad Request: " + fmt.Sprintf(format, args...)}
+}
+
+type methodHandler func(s *Server, r *http.Request, p params) (interface{}, *apiError)
+
+var methods = map[string]methodHandler{
+	"getMe":                  (*Server).getMe,
+	"getChat":                (*Server).getChat,
+	"sendChatAction":         (*Server).sendChatAction,
+	"getUpdates":             (*Server).getUpdates,
+	"setWebhook":             (*Server).setWebhook,
+	"deleteWebhook":          (*Server).deleteWebhook,
+	"getWebhookInfo":         (*Server).getWebhookInfo,
+	"sendMessage":            (*Server).sendMessage,
+	"sendPhoto":              (*Server).sendPhoto,
+	"sendDocument":           (*Server).sendDocument,
+	"editMessageText":        (*Server).editMessageText,
+	"editMessageReplyMarkup": (*Server).editMessageReplyMarkup,
+	"deleteMessage":          (*Server).deleteMessage,
+	"deleteMessages":         (*Server).deleteMessages,
+	"answerCallbackQuery":    (*Server).answerCallbackQuery,
+	"getFile":                (*Server).getFile,
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if path, ok := strings.CutPrefix(r.URL.Path, "/file/bot"+s.Token+"/"); ok {
+		s.serveFile(w, path)
+		return
+	}
+
+	method, ok := strings.CutPrefix(r.URL.Path, "/bot"+s.Token+"/")
+	if !ok {
+		writeError(w, &apiError{http.StatusUnauthorized, "Unauthorized"})
+		return
+	}
+
+	handler, ok := methods[method]
+	if !ok {
+		writeError(w, &apiError{http.StatusNotFound, "Not Found"})
+		return
+	}
+
+	p, err := parseParams(r)
+	if err != nil {
+		writeError(w, badRequest("%s", err))
+		return
+	}
+
+	if method != "getUpdates" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	result, apiErr := handler(s, r, p)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":     true,
+		"result": result,
+	})
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, f := range s.files {
+		if f.path == path {
+			w.Write(f.data)
+			return
+		}
+	}
+
+	http.NotFound(w, nil)
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":          false,
+		"error_code":  err.code,
+		"description": err.description,
+	})
+}
+
+func (s *Server) getMe(r *http.Request, p params) (interface{}, *apiError) {
+	return s.Bot, nil
+}
+
+func (s *Server) chat(p params) (*Chat, *apiError) {
+	chatID, err := p.int64("chat_id")
+	if err != nil {
+		return nil, badRequest("%s", err)
+	}
+
+	chat, ok := s.chats[chatID]
+	if !ok {
+		return nil, badRequest("chat not found")
+	}
+
+	return chat, nil
+}
+
+func (s *Server) getChat(r *http.Request, p params) (interface{}, *apiError) {
+	return s.chat(p)
+}
+
+func (s *Server) sendChatAction(r *http.Request, p params) (interface{}, *apiError) {
+	if _, err := s.chat(p); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+func (s *Server) getUpdates(r *http.Request, p params) (interface{}, *apiError) {
+	offset, _ := p.int64("offset")
+	limit, _ := p.int64("limit")
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	timeout, _ := p.int64("timeout")
+	deadline := time.After(min(time.Duration(timeout)*time.Second, maxPollTimeout))
+
+	for {
+		s.mu.Lock()
+		if s.webhook != nil {
+			s.mu.Unlock()
+			return nil, &apiError{http.StatusConflict, "Conflict: can't use getUpdates method while webhook is active; use deleteWebhook to delete the webhook first"}
+		}
+
+		// Updates before offset are confirmed
+		for len(s.updates) > 0 && int64(s.updates[0].UpdateID) < offset {
+			s.updates = s.updates[1:]
+		}
+		updates := s.updates[:min(int64(len(s.updates)), limit)]
+		changed := s.changed
+		s.mu.Unlock()
+
+		if len(updates) > 0 || timeout <= 0 {
+			return updates, nil
+		}
+
+		select {
+		case <-changed:
+		case <-deadline:
+			return []update{}, nil
+		case <-r.Context().Done():
+			return []update{}, nil
+		}
+	}
+}
+
+func (s *Server) setWebhook(r *http.Request, p params) (interface{}, *apiError) {
+	webhookURL := p.string("url")
+	if webhookURL == "" {
+		s.webhook = nil
+		return true, nil
+	}
+
+	s.webhook = &webhook{
+		url:         webhookURL,
+		secretToken: p.string("secret_token"),
+	}
+	s.webhookError = ""
+
+	// Pending updates are delivered to the new webhook in background,
+	// the bot may start listening after setWebhook
+	if p.bool("drop_pending_updates") {
+		s.updates = nil
+	}
+	s.startDelivery()
+
+	return true, nil
+}
+
+func (s *Server) deleteWebhook(r *http.Request, p params) (interface{}, *apiError) {
+	s.webhook = nil
+	if p.bool("drop_pending_updates") {
+		s.updates = nil
+	}
+
+	return true, nil
+}
+
+func (s *Server) getWebhookInfo(r *http.Request, p params) (interface{}, *apiError) {
+	info := micha.WebhookInfo{}
+	if s.webhook != nil {
+		info.URL = s.webhook.url
+	}
+	info.PendingUpdateCount = len(s.updates)
+	info.LastErrorMessage = s.webhookError
+
+	return info, nil
+}
+
+func (s *Server) newBotMessage(p params, m *message) (interface{}, *apiError) {
+	chat, err := s.chat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	replyTo, _ := p.int64("reply_to_message_id")
+	reply := struct {
+		MessageID int64 `json:"message_id"`
+	}{}
+	if err := p.decode("reply_parameters", &reply); err == nil && reply.MessageID > 0 {
+		replyTo = reply.MessageID
+	}
+	if replyTo > 0 {
+		m.ReplyToMessage = s.findMessage(chat.ID, replyTo)
+	}
+	if err := setReplyMarkup(m, p); err != nil {
+		return nil, err
+	}
+
+	return s.addMessage(chat, s.Bot, m), nil
+}
+
+// setReplyMarkup - store reply markup of the message, inline keyboard is decoded for the accessors
+func setReplyMarkup(m *message, p params) *apiError {
+	keyboard := micha.InlineKeyboardMarkup{}
+	if err := p.decode("reply_markup", &keyboard); err != nil {
+		return badRequest("can't parse reply keyboard markup JSON object: %s", err)
+	}
+
+	m.ReplyMarkup = p["reply_markup"]
+	m.keyboard = keyboard
+
+	return nil
+}
+
+func (s *Server) sendMessage(r *http.Request, p params) (interface{}, *apiError) {
+	text := p.string("text")
+	if text == "" {
+		return nil, badRequest("message text is empty")
+	}
+
+	m := &message{Text: text}
+	if err := p.decode("entities", &m.Entities); err != nil {
+		return nil, badRequest("can't parse entities: %s", err)
+	}
+
+	return s.newBotMessage(p, m)
+}
+
+// uploadFile - return id of existing file or store uploaded one
+func (s *Server) uploadFile(r *http.Request, p params, field string) (micha.File, *apiError) {
+	if fileID := p.string(field); fileID != "" {
+		if _, ok := s.files[fileID]; !ok {
+			return micha.File{}, badRequest("wrong file identifier/HTTP URL specified")
+		}
+		return micha.File{FileID: fileID, FileSize: uint64(len(s.files[fileID].data))}, nil
+	}
+
+	if r.MultipartForm == nil || len(r.MultipartForm.File[field]) == 0 {
+		return micha.File{}, badRequest("there is no %s in the request", field)
+	}
+
+	header := r.MultipartForm.File[field][0]
+	f, err := header.Open()
+	if err != nil {
+		return micha.File{}, badRequest("%s", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return micha.File{}, badRequest("%s", err)
+	}
+
+	return s.addFile(fmt.Sprintf("%s-%d", field, len(s.files)+1), data), nil
+}
+
+func (s *Server) sendPhoto(r *http.Request, p params) (interface{}, *apiError) {
+	f, err := s.uploadFile(r, p, "photo")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newBotMessage(p, &message{
+		Caption: p.string("caption"),
+		Photo:   []micha.PhotoSize{{FileID: f.FileID, FileSize: f.FileSize}},
+	})
+}
+
+func (s *Server) sendDocument(r *http.Request, p params) (interface{}, *apiError) {
+	f, err := s.uploadFile(r, p, "document")
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := ""
+	if r.MultipartForm != nil && len(r.MultipartForm.File["document"]) > 0 {
+		fileName = r.MultipartForm.File["document"][0].Filename
+	}
+
+	return s.newBotMessage(p, &message{
+		Caption:  p.string("caption"),
+		Document: &micha.Document{FileID: f.FileID, FileName: fileName, FileSize: f.FileSize},
+	})
+}
+
+// editableMessage - return bot message by chat_id and message_id
+func (s *Server) editableMessage(p params) (*message, *apiError) {
+	if p.string("inline_message_id") != "" {
+		return nil, badRequest("inline messages are not supported")
+	}
+
+	chat, err := s.chat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, _ := p.int64("message_id")
+	m := s.findMessage(chat.ID, messageID)
+	if m == nil {
+		return nil, badRequest("message to edit not found")
+	}
+	if m.From.ID != s.Bot.ID {
+		return nil, badRequest("message can't be edited")
+	}
+
+	return m, nil
+}
+
+func (s *Server) editMessageText(r *http.Request, p params) (interface{}, *apiError) {
+	m, err := s.editableMessage(p)
+	if err != nil {
+		return nil, err
+	}
+
+	text := p.string("text")
+	if text == "" {
+		return nil, badRequest("message text is empty")
+	}
+	if text == m.Text && bytes.Equal(p["reply_markup"], m.ReplyMarkup) {
+		return nil, badRequest("message is not modified: specified new message content and reply markup are exactly the same as a current content and reply markup of the message")
+	}
+
+	m.Text = text
+	m.Entities = nil
+	if err := p.decode("entities", &m.Entities); err != nil {
+		return nil, badRequest("can't parse entities: %s", err)
+	}
+	if err := setReplyMarkup(m, p); err != nil {
+		return nil, err
+	}
+	m.EditDate = time.Now().Unix()
+	s.notify()
+
+	return m, nil
+}
+
+func (s *Server) editMessageReplyMarkup(r *http.Request, p params) (interface{}, *apiError) {
+	m, err := s.editableMessage(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setReplyMarkup(m, p); err != nil {
+		return nil, err
+	}
+	m.EditDate = time.Now().Unix()
+	s.notify()
+
+	return m, nil
+}
+
+func (s *Server) removeMessage(chatID, messageID int64) bool {
+	messages := s.messages[chatID]
+	for i, m := range messages {
+		if m.MessageID == messageID {
+			s.messages[chatID] = append(messages[:i:i], messages[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) deleteMessage(r *http.Request, p params) (interface{}, *apiError) {
+	chat, err := s.chat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, _ := p.int64("message_id")
+	if !s.removeMessage(chat.ID, messageID) {
+		return nil, badRequest("message to delete not found")
+	}
+	s.notify()
+
+	return true, nil
+}
+
+func (s *Server) deleteMessages(r *http.Request, p params) (interface{}, *apiError) {
+	chat, err := s.chat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	messageIDs := []int64{}
+	if err := p.decode("message_ids", &messageIDs); err != nil {
+		return nil, badRequest("can't parse message identifiers: %s", err)
+	}
+
+	// Missing messages are skipped
+	for _, messageID := range messageIDs {
+		s.removeMessage(chat.ID, messageID)
+	}
+	s.notify()
+
+	return true, nil
+}
+
+func (s *Server) answerCallbackQuery(r *http.Request, p params) (interface{}, *apiError) {
+	answer := CallbackAnswer{
+		CallbackQueryID: p.string("callback_query_id"),
+		Text:            p.string("text"),
+		ShowAlert:       p.bool("show_alert"),
+		URL:             p.string("url"),
+	}
+	cacheTime, _ := p.int64("cache_time")
+	answer.CacheTime = int(cacheTime)
+
+	queryID, err := strconv.Atoi(answer.CallbackQueryID)
+	if err != nil || queryID <= 0 || queryID > s.lastCallbackID {
+		return nil, badRequest("query is too old and response timeout expired or query ID is invalid")
+	}
+
+	s.callbackAnswers = append(s.callbackAnswers, answer)
+	s.notify()
+
+	return true, nil
+}
+
+func (s *Server) getFile(r *http.Request, p params) (interface{}, *apiError) {
+	fileID := p.string("file_id")
+	f, ok := s.files[fileID]
+	if !ok {
+		return nil, badRequest("invalid file_id")
+	}
+
+	return micha.File{FileID: fileID, FileSize: uint64(len(f.data)), FilePath: f.path}, nil
+}
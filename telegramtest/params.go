@@ -0,0 +1,107 @@
+package telegramtest
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// params - request parameters from query string, JSON body or form
+type params map[string]json.RawMessage
+
+func parseParams(r *http.Request) (params, error) {
+	p := params{}
+	for key, values := range r.URL.Query() {
+		p.setString(key, values)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		body := map[string]json.RawMessage{}
+		// Methods without parameters are called with empty body
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+			return nil, err
+		}
+		for key, value := range body {
+			p[key] = value
+		}
+
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return nil, err
+		}
+		for key, values := range r.MultipartForm.Value {
+			p.setString(key, values)
+		}
+
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		for key, values := range r.PostForm {
+			p.setString(key, values)
+		}
+	}
+
+	return p, nil
+}
+
+// setString - store form value, JSON values (objects, arrays, numbers) are kept as is
+func (p params) setString(key string, values []string) {
+	if len(values) > 1 {
+		data, _ := json.Marshal(values)
+		p[key] = data
+		return
+	}
+
+	value := values[0]
+	if json.Valid([]byte(value)) {
+		p[key] = json.RawMessage(value)
+		return
+	}
+
+	data, _ := json.Marshal(value)
+	p[key] = data
+}
+
+func (p params) string(key string) string {
+	raw, ok := p[key]
+	if !ok {
+		return ""
+	}
+
+	s := ""
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return string(raw)
+	}
+
+	return s
+}
+
+func (p params) int64(key string) (int64, error) {
+	return strconv.ParseInt(strings.Trim(string(p[key]), `"`), 10, 64)
+}
+
+func (p params) bool(key string) bool {
+	b, _ := strconv.ParseBool(strings.Trim(string(p[key]), `"`))
+	return b
+}
+
+// decode - unmarshal JSON value, value may be passed as JSON encoded string
+func (p params) decode(key string, target interface{}) error {
+	raw, ok := p[key]
+	if !ok {
+		return nil
+	}
+
+	s := ""
+	if json.Unmarshal(raw, &s) == nil {
+		raw = json.RawMessage(s)
+	}
+
+	return json.Unmarshal(raw, target)
+}
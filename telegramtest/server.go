@@ -0,0 +1,460 @@
+// Package telegramtest provides an in-memory fake of the Telegram Bot API for tests.
+//
+//	server := telegramtest.NewServer("123:token")
+//	defer server.Close()
+//
+//	bot, _ := micha.NewBot(server.Token, micha.WithAPIServer(server.URL))
+//	go bot.Start()
+//
+//	server.SendFromUser(user, "/start")
+//	messages, err := server.WaitSent(user.ID, 1, time.Second)
+package telegramtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/onrik/micha"
+)
+
+var (
+	ErrTimeout = errors.New("telegramtest: timeout")
+)
+
+// Chat - chat known to the server
+type Chat struct {
+	ID   int64          `json:"id"`
+	Type micha.ChatType `json:"type"`
+
+	// Optional
+	Title     string `json:"title,omitempty"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+// CallbackAnswer - answerCallbackQuery call made by the bot
+type CallbackAnswer struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert,omitempty"`
+	URL             string `json:"url,omitempty"`
+	CacheTime       int    `json:"cache_time,omitempty"`
+}
+
+type message struct {
+	MessageID      int64                 `json:"message_id"`
+	From           *micha.User           `json:"from,omitempty"`
+	Date           int64                 `json:"date"`
+	Chat           Chat                  `json:"chat"`
+	EditDate       int64                 `json:"edit_date,omitempty"`
+	ReplyToMessage *message              `json:"reply_to_message,omitempty"`
+	Text           string                `json:"text,omitempty"`
+	Entities       []micha.MessageEntity `json:"entities,omitempty"`
+	Caption        string                `json:"caption,omitempty"`
+	Photo          []micha.PhotoSize     `json:"photo,omitempty"`
+	Document       *micha.Document       `json:"document,omitempty"`
+	ReplyMarkup    json.RawMessage       `json:"reply_markup,omitempty"`
+
+	// Inline keyboard decoded from ReplyMarkup when the message is sent or edited
+	keyboard micha.InlineKeyboardMarkup
+}
+
+type callbackQuery struct {
+	ID           string     `json:"id"`
+	From         micha.User `json:"from"`
+	Message      *message   `json:"message,omitempty"`
+	ChatInstance string     `json:"chat_instance"`
+	Data         string     `json:"data,omitempty"`
+}
+
+type update struct {
+	UpdateID      uint64         `json:"update_id"`
+	Message       *message       `json:"message,omitempty"`
+	CallbackQuery *callbackQuery `json:"callback_query,omitempty"`
+}
+
+type file struct {
+	path string
+	data []byte
+}
+
+type webhook struct {
+	url         string
+	secretToken string
+}
+
+// Delay before the next attempt to deliver update to webhook which failed
+const webhookRetryInterval = 50 * time.Millisecond
+
+// Server - fake Telegram Bot API server.
+// Point the bot to it with micha.WithAPIServer(server.URL).
+type Server struct {
+	*httptest.Server
+	Token string
+	Bot   micha.User
+
+	mu              sync.Mutex
+	changed         chan struct{}
+	chats           map[int64]*Chat
+	messages        map[int64][]*message
+	lastMessageID   map[int64]int64
+	updates         []update
+	lastUpdateID    uint64
+	lastCallbackID  int
+	files           map[string]file
+	callbackAnswers []CallbackAnswer
+	webhook         *webhook
+	webhookError    string
+	delivering      bool
+
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+	deliveries sync.WaitGroup
+}
+
+// NewServer - start new fake server for bot token ("<bot id>:<secret>")
+func NewServer(token string) *Server {
+	botID, _ := strconv.ParseInt(strings.SplitN(token, ":", 2)[0], 10, 64)
+
+	s := &Server{
+		Token: token,
+		Bot: micha.User{
+			ID:        botID,
+			IsBot:     true,
+			FirstName: "Test Bot",
+			Username:  "test_bot",
+		},
+		changed:       make(chan struct{}),
+		chats:         map[int64]*Chat{},
+		messages:      map[int64][]*message{},
+		lastMessageID: map[int64]int64{},
+		files:         map[string]file{},
+	}
+	s.ctx, s.cancelFunc = context.WithCancel(context.Background())
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Close - stop delivering updates to webhook and shut down the server
+func (s *Server) Close() {
+	s.cancelFunc()
+	s.deliveries.Wait()
+	s.Server.Close()
+}
+
+// notify wakes up everyone waiting for state changes, must be called with mu held
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// AddChat - register group, supergroup or channel
+func (s *Server) AddChat(chat Chat) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chats[chat.ID] = &chat
+}
+
+// AddFile - register file which can be requested by getFile and downloaded
+func (s *Server) AddFile(fileID string, data []byte) micha.File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addFile(fileID, data)
+}
+
+func (s *Server) addFile(fileID string, data []byte) micha.File {
+	f := file{path: "files/" + fileID, data: data}
+	s.files[fileID] = f
+
+	return micha.File{FileID: fileID, FileSize: uint64(len(data)), FilePath: f.path}
+}
+
+func (s *Server) privateChat(user micha.User) *Chat {
+	chat, ok := s.chats[user.ID]
+	if !ok {
+		chat = &Chat{
+			ID:        user.ID,
+			Type:      micha.CHAT_TYPE_PRIVATE,
+			Username:  user.Username,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+		}
+		s.chats[user.ID] = chat
+	}
+
+	return chat
+}
+
+func (s *Server) addMessage(chat *Chat, from micha.User, m *message) *message {
+	s.lastMessageID[chat.ID]++
+	m.MessageID = s.lastMessageID[chat.ID]
+	m.From = &from
+	m.Chat = *chat
+	m.Date = time.Now().Unix()
+	s.messages[chat.ID] = append(s.messages[chat.ID], m)
+	s.notify()
+
+	return m
+}
+
+func (s *Server) findMessage(chatID, messageID int64) *message {
+	for _, m := range s.messages[chatID] {
+		if m.MessageID == messageID {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) pushUpdate(u update) {
+	s.lastUpdateID++
+	u.UpdateID = s.lastUpdateID
+	s.updates = append(s.updates, u)
+	s.startDelivery()
+	s.notify()
+}
+
+// startDelivery - start delivering pending updates to webhook if it is set, must be called with mu held
+func (s *Server) startDelivery() {
+	if s.webhook == nil || s.delivering || s.ctx.Err() != nil {
+		return
+	}
+
+	s.delivering = true
+	s.deliveries.Add(1)
+	go s.deliverPending()
+}
+
+// deliverPending - deliver pending updates to webhook one by one in order.
+// Update stays pending until webhook accepts it, failed delivery is retried like Telegram does.
+func (s *Server) deliverPending() {
+	defer s.deliveries.Done()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.webhook != nil && len(s.updates) > 0 && s.ctx.Err() == nil {
+		hook, u := *s.webhook, s.updates[0]
+
+		// Unlock while the bot handles update, it will probably call the server
+		s.mu.Unlock()
+		err := s.deliver(hook, u)
+		s.mu.Lock()
+
+		if err == nil {
+			if len(s.updates) > 0 && s.updates[0].UpdateID == u.UpdateID {
+				s.updates = s.updates[1:]
+			}
+			s.notify()
+			continue
+		}
+
+		s.webhookError = err.Error()
+		changed := s.changed
+		s.mu.Unlock()
+		select {
+		case <-time.After(webhookRetryInterval):
+		case <-changed:
+		case <-s.ctx.Done():
+		}
+		s.mu.Lock()
+	}
+
+	s.delivering = false
+}
+
+func (s *Server) deliver(hook webhook, u update) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(s.ctx, http.MethodPost, hook.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if hook.secretToken != "" {
+		request.Header.Set("X-Telegram-Bot-Api-Secret-Token", hook.secretToken)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegramtest: webhook returned %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// SendFromUser - send text message from user to the bot in private chat.
+// Update is queued for getUpdates or delivered to webhook in background.
+func (s *Server) SendFromUser(user micha.User, text string) (micha.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.send(s.privateChat(user), user, text)
+}
+
+// SendToChat - send text message from user to registered chat
+func (s *Server) SendToChat(chatID int64, user micha.User, text string) (micha.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chat, ok := s.chats[chatID]
+	if !ok {
+		return micha.Message{}, fmt.Errorf("telegramtest: chat %d not found", chatID)
+	}
+
+	return s.send(chat, user, text)
+}
+
+func (s *Server) send(chat *Chat, user micha.User, text string) (micha.Message, error) {
+	m := s.addMessage(chat, user, &message{Text: text})
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		m.Entities = []micha.MessageEntity{{Type: micha.MESSAGE_ENTITY_BOT_COMMAND, Length: len(utf16.Encode([]rune(command)))}}
+	}
+
+	result := toMessage(m)
+	s.pushUpdate(update{Message: m})
+
+	return result, nil
+}
+
+// PressButton - press inline keyboard button with callback data on the message.
+// Returns callback query id.
+func (s *Server) PressButton(chatID, messageID int64, user micha.User, data string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.findMessage(chatID, messageID)
+	if m == nil {
+		return "", fmt.Errorf("telegramtest: message %d not found in chat %d", messageID, chatID)
+	}
+
+	s.lastCallbackID++
+	query := callbackQuery{
+		ID:           strconv.Itoa(s.lastCallbackID),
+		From:         user,
+		Message:      m,
+		ChatInstance: strconv.FormatInt(chatID, 10),
+		Data:         data,
+	}
+
+	s.pushUpdate(update{CallbackQuery: &query})
+
+	return query.ID, nil
+}
+
+// Messages - return all messages of the chat
+func (s *Server) Messages(chatID int64) []micha.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.chatMessages(chatID, false)
+}
+
+// SentMessages - return messages sent by the bot to the chat
+func (s *Server) SentMessages(chatID int64) []micha.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.chatMessages(chatID, true)
+}
+
+func (s *Server) chatMessages(chatID int64, sentOnly bool) []micha.Message {
+	messages := []micha.Message{}
+	for _, m := range s.messages[chatID] {
+		if !sentOnly || m.From.ID == s.Bot.ID {
+			messages = append(messages, toMessage(m))
+		}
+	}
+
+	return messages
+}
+
+// WaitSent - wait until the bot sends at least n messages to the chat
+func (s *Server) WaitSent(chatID int64, n int, timeout time.Duration) ([]micha.Message, error) {
+	deadline := time.After(timeout)
+	for {
+		s.mu.Lock()
+		messages := s.chatMessages(chatID, true)
+		changed := s.changed
+		s.mu.Unlock()
+
+		if len(messages) >= n {
+			return messages, nil
+		}
+
+		select {
+		case <-changed:
+		case <-deadline:
+			return messages, ErrTimeout
+		}
+	}
+}
+
+// CallbackAnswers - return answers to callback queries
+func (s *Server) CallbackAnswers() []CallbackAnswer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]CallbackAnswer(nil), s.callbackAnswers...)
+}
+
+// PendingUpdates - return number of updates not confirmed by the bot yet.
+// Updates are confirmed by getUpdates call with greater offset or accepted by webhook.
+func (s *Server) PendingUpdates() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.updates)
+}
+
+func toMessage(m *message) micha.Message {
+	result := micha.Message{
+		MessageID: m.MessageID,
+		Date:      micha.UnixTime(m.Date),
+		Chat: micha.Chat{
+			ID:        micha.ChatIDFromInt(m.Chat.ID),
+			Type:      m.Chat.Type,
+			Title:     m.Chat.Title,
+			Username:  m.Chat.Username,
+			FirstName: m.Chat.FirstName,
+			LastName:  m.Chat.LastName,
+		},
+		From:        m.From,
+		EditDate:    micha.UnixTime(m.EditDate),
+		Text:        m.Text,
+		Entities:    m.Entities,
+		Caption:     m.Caption,
+		Photo:       m.Photo,
+		Document:    m.Document,
+		ReplyMarkup: m.keyboard,
+	}
+	if m.ReplyToMessage != nil {
+		replyTo := toMessage(m.ReplyToMessage)
+		result.ReplyToMessage = &replyTo
+	}
+
+	return result
+}
@@ -0,0 +1,269 @@
+package telegramtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onrik/micha"
+	"github.com/stretchr/testify/require"
+)
+
+var testUser = micha.User{ID: 42, FirstName: "Alice", Username: "alice"}
+
+func newTestBot(t *testing.T, server *Server) *micha.Bot {
+	bot, err := micha.NewBot(server.Token, micha.WithAPIServer(server.URL), micha.WithTimeout(1))
+	require.Nil(t, err)
+
+	return bot
+}
+
+func TestEchoBot(t *testing.T) {
+	server := NewServer("123:token")
+	defer server.Close()
+
+	bot := newTestBot(t, server)
+	go bot.Start()
+	defer bot.Stop()
+
+	go func() {
+		for update := range bot.Updates() {
+			if update.Message != nil {
+				bot.SendMessage(update.Message.Chat.ID, "echo: "+update.Message.Text, nil)
+			}
+		}
+	}()
+
+	sent, err := server.SendFromUser(testUser, "hello")
+	require.Nil(t, err)
+	require.Equal(t, int64(1), sent.MessageID)
+	require.Equal(t, micha.ChatID("42"), sent.Chat.ID)
+
+	messages, err := server.WaitSent(testUser.ID, 1, time.Second)
+	require.Nil(t, err)
+	require.Equal(t, "echo: hello", messages[0].Text)
+	require.Equal(t, int64(2), messages[0].MessageID)
+	require.Equal(t, int64(123), messages[0].From.ID)
+	require.Len(t, server.Messages(testUser.ID), 2)
+
+	_, err = server.SendFromUser(testUser, "/start now")
+	require.Nil(t, err)
+	messages, err = server.WaitSent(testUser.ID, 2, time.Second)
+	require.Nil(t, err)
+	require.Equal(t, "echo: /start now", messages[1].Text)
+
+	// Entity length is measured in UTF-16 code units
+	sent, err = server.SendFromUser(testUser, "/старт😀 now")
+	require.Nil(t, err)
+	require.Equal(t, []micha.MessageEntity{{Type: micha.MESSAGE_ENTITY_BOT_COMMAND, Length: 8}}, sent.Entities)
+}
+
+func TestEditAndDelete(t *testing.T) {
+	server := NewServer("123:token")
+	defer server.Close()
+	server.AddChat(Chat{ID: -100, Type: micha.CHAT_TYPE_SUPERGROUP, Title: "Group"})
+	bot := newTestBot(t, server)
+
+	userMessage, err := server.SendToChat(-100, testUser, "hi")
+	require.Nil(t, err)
+
+	keyboard := micha.InlineKeyboardMarkup{InlineKeyboard: [][]micha.InlineKeyboardButton{{{Text: "OK", CallbackData: "ok"}}}}
+	message, err := bot.SendMessage("-100", "question", &micha.SendMessageOptions{
+		ReplyMarkup:     keyboard,
+		ReplyParameters: &micha.ReplyParameters{MessageID: userMessage.MessageID},
+	})
+	require.Nil(t, err)
+	require.Equal(t, int64(2), message.MessageID)
+	require.Equal(t, userMessage.MessageID, message.ReplyToMessage.MessageID)
+	require.Equal(t, keyboard.InlineKeyboard, server.SentMessages(-100)[0].ReplyMarkup.InlineKeyboard)
+	require.Equal(t, "hi", server.SentMessages(-100)[0].ReplyToMessage.Text)
+
+	response, err := http.Post(server.URL+"/bot"+server.Token+"/sendMessage", "application/json",
+		bytes.NewBufferString(`{"chat_id":-100,"text":"bad","reply_markup":{"inline_keyboard":1}}`))
+	require.Nil(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusBadRequest, response.StatusCode)
+	require.Len(t, server.SentMessages(-100), 1)
+
+	edited, err := bot.EditMessageText("-100", message.MessageID, "", "answer", nil)
+	require.Nil(t, err)
+	require.Equal(t, "answer", edited.Text)
+	require.NotZero(t, edited.EditDate)
+
+	// User messages can't be edited
+	_, err = bot.EditMessageText("-100", userMessage.MessageID, "", "changed", nil)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "message can't be edited")
+
+	_, err = bot.SendMessage("-200", "lost", nil)
+	require.Contains(t, err.Error(), "chat not found")
+
+	ok, err := bot.DeleteMessage("-100", message.MessageID)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Empty(t, server.SentMessages(-100))
+
+	_, err = bot.DeleteMessage("-100", message.MessageID)
+	require.NotNil(t, err)
+}
+
+func TestCallbackQuery(t *testing.T) {
+	server := NewServer("123:token")
+	defer server.Close()
+	bot := newTestBot(t, server)
+
+	_, err := server.SendFromUser(testUser, "hi")
+	require.Nil(t, err)
+	message, err := bot.SendMessage("42", "press", nil)
+	require.Nil(t, err)
+
+	queryID, err := server.PressButton(42, message.MessageID, testUser, "ok")
+	require.Nil(t, err)
+
+	go bot.Start()
+	defer bot.Stop()
+
+	var query *micha.CallbackQuery
+	for update := range bot.Updates() {
+		if update.CallbackQuery != nil {
+			query = update.CallbackQuery
+			break
+		}
+	}
+	require.Equal(t, queryID, query.ID)
+	require.Equal(t, "ok", query.Data)
+	require.Equal(t, message.MessageID, query.Message.MessageID)
+
+	require.Nil(t, bot.AnswerCallbackQuery(query.ID, &micha.AnswerCallbackQueryOptions{Text: "Done", ShowAlert: true}))
+	require.Equal(t, []CallbackAnswer{{CallbackQueryID: queryID, Text: "Done", ShowAlert: true}}, server.CallbackAnswers())
+
+	require.NotNil(t, bot.AnswerCallbackQuery("100", nil))
+}
+
+func TestWebhook(t *testing.T) {
+	server := NewServer("123:token")
+	defer server.Close()
+	bot := newTestBot(t, server)
+
+	received := make(chan string, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r.Header.Get("X-Telegram-Bot-Api-Secret-Token") + " " + string(body)
+	}))
+	defer webhook.Close()
+
+	_, err := server.SendFromUser(testUser, "queued")
+	require.Nil(t, err)
+	require.Equal(t, 1, server.PendingUpdates())
+
+	require.Nil(t, bot.SetWebhook(webhook.URL, nil))
+	require.Contains(t, <-received, `"text":"queued"`)
+	require.Eventually(t, func() bool { return server.PendingUpdates() == 0 }, time.Second, time.Millisecond)
+
+	info, err := bot.GetWebhookInfo()
+	require.Nil(t, err)
+	require.Equal(t, webhook.URL, info.URL)
+
+	_, err = server.SendFromUser(testUser, "direct")
+	require.Nil(t, err)
+	require.Contains(t, <-received, `"text":"direct"`)
+
+	// getUpdates conflicts with webhook, polling is stopped
+	poller := newTestBot(t, server)
+	poller.Start()
+	_, ok := <-poller.Updates()
+	require.False(t, ok)
+
+	require.Nil(t, bot.DeleteWebhook())
+	_, err = server.SendFromUser(testUser, "polled")
+	require.Nil(t, err)
+	require.Equal(t, 1, server.PendingUpdates())
+}
+
+func TestWebhookRetry(t *testing.T) {
+	server := NewServer("123:token")
+	defer server.Close()
+	bot := newTestBot(t, server)
+
+	_, err := server.SendFromUser(testUser, "first")
+	require.Nil(t, err)
+	_, err = server.SendFromUser(testUser, "second")
+	require.Nil(t, err)
+
+	// Webhook is set before the bot starts listening
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	webhookURL := "http://" + listener.Addr().String()
+	listener.Close()
+	require.Nil(t, bot.SetWebhook(webhookURL, nil))
+
+	require.Eventually(t, func() bool {
+		info, err := bot.GetWebhookInfo()
+		return err == nil && info.LastErrorMessage != ""
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 2, server.PendingUpdates())
+
+	received := make(chan string, 2)
+	webhook := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		update := micha.Update{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&update))
+		received <- update.Message.Text
+	}))
+	webhook.Listener.Close()
+	webhook.Listener, err = net.Listen("tcp", listener.Addr().String())
+	require.Nil(t, err)
+	webhook.Start()
+	defer webhook.Close()
+
+	require.Equal(t, "first", <-received)
+	require.Equal(t, "second", <-received)
+	require.Eventually(t, func() bool { return server.PendingUpdates() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestFiles(t *testing.T) {
+	server := NewServer("123:token")
+	defer server.Close()
+	bot := newTestBot(t, server)
+	server.AddChat(Chat{ID: -100, Type: micha.CHAT_TYPE_GROUP, Title: "Group"})
+
+	message, err := bot.SendDocumentFile("-100", bytes.NewBufferString("report"), "report.txt", &micha.SendDocumentOptions{Caption: "Report"})
+	require.Nil(t, err)
+	require.Equal(t, "Report", message.Caption)
+	require.Equal(t, "report.txt", message.Document.FileName)
+
+	file, err := bot.GetFile(message.Document.FileID)
+	require.Nil(t, err)
+	require.Equal(t, uint64(6), file.FileSize)
+
+	response, err := http.Get(bot.DownloadFileURL(file.FilePath))
+	require.Nil(t, err)
+	defer response.Body.Close()
+	data, _ := io.ReadAll(response.Body)
+	require.Equal(t, "report", string(data))
+
+	// Resend by file id
+	message, err = bot.SendDocument("-100", file.FileID, nil)
+	require.Nil(t, err)
+	require.Equal(t, file.FileID, message.Document.FileID)
+
+	photo := server.AddFile("photo-id", []byte("jpeg"))
+	message, err = bot.SendPhoto("-100", photo.FileID, nil)
+	require.Nil(t, err)
+	require.Equal(t, "photo-id", message.Photo[0].FileID)
+
+	_, err = bot.SendPhoto("-100", "unknown", nil)
+	require.Contains(t, err.Error(), "wrong file identifier")
+}
+
+func TestUnauthorized(t *testing.T) {
+	server := NewServer("123:token")
+	defer server.Close()
+
+	_, err := micha.NewBot("123:other", micha.WithAPIServer(server.URL))
+	require.Equal(t, micha.HTTPError{StatusCode: http.StatusUnauthorized}, err)
+}
@@ -0,0 +1,168 @@
+package micha
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Text is a builder of formatted text.
+// The result can be sent either as plain text with entities or as escaped HTML/MarkdownV2 markup:
+//
+//	text := micha.NewText().Bold("Hi ").Mention(user).Plain(", your code: ").Code(code)
+//	bot.SendMessage(chatID, text.String(), &micha.SendMessageOptions{Entities: text.Entities()})
+type Text struct {
+	builder  strings.Builder
+	length   int
+	entities []MessageEntity
+}
+
+// NewText - create new text builder
+func NewText() *Text {
+	return &Text{}
+}
+
+func (t *Text) add(entity MessageEntity, text string) *Text {
+	entity.Offset = t.length
+	entity.Length = utf16Len(text)
+	t.builder.WriteString(text)
+	t.length += entity.Length
+	if entity.Length > 0 {
+		t.entities = append(t.entities, entity)
+	}
+
+	return t
+}
+
+// Plain - append text without formatting
+func (t *Text) Plain(text string) *Text {
+	t.builder.WriteString(text)
+	t.length += utf16Len(text)
+
+	return t
+}
+
+// Plainf - append formatted text without formatting
+func (t *Text) Plainf(format string, args ...any) *Text {
+	return t.Plain(fmt.Sprintf(format, args...))
+}
+
+// Bold - append bold text
+func (t *Text) Bold(text string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_BOLD}, text)
+}
+
+// Italic - append italic text
+func (t *Text) Italic(text string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_ITALIC}, text)
+}
+
+// Underline - append underlined text
+func (t *Text) Underline(text string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_UNDERLINE}, text)
+}
+
+// Strikethrough - append strikethrough text
+func (t *Text) Strikethrough(text string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_STRIKETHROUGH}, text)
+}
+
+// Spoiler - append text hidden under spoiler
+func (t *Text) Spoiler(text string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_SPOILER}, text)
+}
+
+// Code - append inline monowidth text
+func (t *Text) Code(text string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_CODE}, text)
+}
+
+// Pre - append monowidth code block, language is optional
+func (t *Text) Pre(text, language string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_PRE, Language: language}, text)
+}
+
+// Link - append clickable text
+func (t *Text) Link(text, url string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_TEXT_LINK, URL: url}, text)
+}
+
+// Mention - append mention of the user by full name, works for users without username
+func (t *Text) Mention(user User) *Text {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" {
+		name = user.Username
+	}
+
+	return t.MentionText(name, user)
+}
+
+// MentionText - append mention of the user with custom text
+func (t *Text) MentionText(text string, user User) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_TEXT_MENTION, User: &user}, text)
+}
+
+// CustomEmoji - append custom emoji, emoji is shown where custom emoji are not available
+func (t *Text) CustomEmoji(emoji, customEmojiID string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_CUSTOM_EMOJI, CustomEmojiID: customEmojiID}, emoji)
+}
+
+// Blockquote - append block quotation
+func (t *Text) Blockquote(text string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_BLOCKQUOTE}, text)
+}
+
+// ExpandableBlockquote - append block quotation collapsed by default
+func (t *Text) ExpandableBlockquote(text string) *Text {
+	return t.add(MessageEntity{Type: MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE}, text)
+}
+
+// Append - append other text with its entities
+func (t *Text) Append(other *Text) *Text {
+	for _, entity := range other.entities {
+		entity.Offset += t.length
+		t.entities = append(t.entities, entity)
+	}
+	t.builder.WriteString(other.builder.String())
+	t.length += other.length
+
+	return t
+}
+
+// Wrap - append other text wrapped into entity, offset and length of the entity are computed.
+// Allows nested formatting:
+//
+//	micha.NewText().Wrap(micha.MessageEntity{Type: micha.MESSAGE_ENTITY_BOLD}, micha.NewText().Plain("bold ").Italic("and italic"))
+func (t *Text) Wrap(entity MessageEntity, other *Text) *Text {
+	entity.Offset = t.length
+	entity.Length = other.length
+	if entity.Length > 0 {
+		t.entities = append(t.entities, entity)
+	}
+
+	return t.Append(other)
+}
+
+// Len - text length in UTF-16 code units
+func (t *Text) Len() int {
+	return t.length
+}
+
+// String - plain text without markup
+func (t *Text) String() string {
+	return t.builder.String()
+}
+
+// Entities - text entities, use with String()
+func (t *Text) Entities() []MessageEntity {
+	return append([]MessageEntity(nil), t.entities...)
+}
+
+// HTML - text with escaped HTML markup, use with PARSE_MODE_HTML
+func (t *Text) HTML() string {
+	return RenderHTML(t.String(), t.entities)
+}
+
+// MarkdownV2 - text with escaped MarkdownV2 markup, use with PARSE_MODE_MARKDOWN_V2
+func (t *Text) MarkdownV2() string {
+	return RenderMarkdownV2(t.String(), t.entities)
+}
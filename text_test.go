@@ -0,0 +1,64 @@
+package micha
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestText(t *testing.T) {
+	user := User{ID: 42, FirstName: "John", LastName: "Doe"}
+	text := NewText().
+		Bold("Hi ").
+		Mention(user).
+		Plain(", 😀 code: ").
+		Code("a_b*c").
+		Plain("\n").
+		Link("docs", "https://example.com/(x)").
+		Plainf(" %d<2", 1)
+
+	require.Equal(t, "Hi John Doe, 😀 code: a_b*c\ndocs 1<2", text.String())
+	require.Equal(t, utf16Len(text.String()), text.Len())
+	require.Equal(t, []MessageEntity{
+		{Type: MESSAGE_ENTITY_BOLD, Offset: 0, Length: 3},
+		{Type: MESSAGE_ENTITY_TEXT_MENTION, Offset: 3, Length: 8, User: &user},
+		{Type: MESSAGE_ENTITY_CODE, Offset: 22, Length: 5},
+		{Type: MESSAGE_ENTITY_TEXT_LINK, Offset: 28, Length: 4, URL: "https://example.com/(x)"},
+	}, text.Entities())
+	require.Equal(t,
+		`<b>Hi </b><a href="tg://user?id=42">John Doe</a>, 😀 code: <code>a_b*c</code>`+"\n"+`<a href="https://example.com/(x)">docs</a> 1&lt;2`,
+		text.HTML(),
+	)
+	require.Equal(t,
+		"*Hi *[John Doe](tg://user?id=42), 😀 code: `a_b*c`\n[docs](https://example.com/(x\\)) 1<2",
+		text.MarkdownV2(),
+	)
+}
+
+func TestTextNested(t *testing.T) {
+	text := NewText().
+		Plain("> ").
+		Wrap(MessageEntity{Type: MESSAGE_ENTITY_BOLD}, NewText().Plain("bold ").Spoiler("secret")).
+		Plain("\n").
+		ExpandableBlockquote("line1\nline2").
+		Plain("\n").
+		Pre("x := 1", "go").
+		CustomEmoji("👍", "123").
+		Bold("")
+
+	require.Equal(t, []MessageEntity{
+		{Type: MESSAGE_ENTITY_BOLD, Offset: 2, Length: 11},
+		{Type: MESSAGE_ENTITY_SPOILER, Offset: 7, Length: 6},
+		{Type: MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE, Offset: 14, Length: 11},
+		{Type: MESSAGE_ENTITY_PRE, Offset: 26, Length: 6, Language: "go"},
+		{Type: MESSAGE_ENTITY_CUSTOM_EMOJI, Offset: 32, Length: 2, CustomEmojiID: "123"},
+	}, text.Entities())
+	require.Equal(t,
+		"\\> *bold ||secret||*\n**>line1\n>line2||\n```go\nx := 1\n```![👍](tg://emoji?id=123)",
+		text.MarkdownV2(),
+	)
+	require.Equal(t,
+		`&gt; <b>bold <tg-spoiler>secret</tg-spoiler></b>`+"\n"+`<blockquote expandable>line1`+"\n"+`line2</blockquote>`+"\n"+`<pre><code class="language-go">x := 1</code></pre><tg-emoji emoji-id="123">👍</tg-emoji>`,
+		text.HTML(),
+	)
+}
@@ -0,0 +1,50 @@
+package micha
+
+import (
+	"time"
+)
+
+// UnixTime - date in unix time (seconds), encoded as JSON number.
+// Zero value means the date is not set.
+type UnixTime int64
+
+// UnixTimeFromTime - convert time to unix time, zero time is converted to zero unix time
+func UnixTimeFromTime(t time.Time) UnixTime {
+	if t.IsZero() {
+		return 0
+	}
+
+	return UnixTime(t.Unix())
+}
+
+// Time - return date as time.Time, zero unix time is returned as zero time
+func (t UnixTime) Time() time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(t), 0)
+}
+
+// IsZero - date is not set
+func (t UnixTime) IsZero() bool {
+	return t == 0
+}
+
+func (t UnixTime) String() string {
+	return t.Time().UTC().Format(time.RFC3339)
+}
+
+// Seconds - duration in seconds, encoded as JSON number.
+// Untyped constants still work, so existing code like Duration: 30 keeps compiling.
+type Seconds int
+
+// SecondsFromDuration - convert duration to seconds, fractions of a second are truncated
+func SecondsFromDuration(d time.Duration) Seconds {
+	return Seconds(d / time.Second)
+}
+
+// Duration - return seconds as time.Duration
+func (s Seconds) Duration() time.Duration {
+	return time.Duration(s) * time.Second
+}
@@ -0,0 +1,47 @@
+package micha
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixTime(t *testing.T) {
+	message := Message{}
+	require.Nil(t, json.Unmarshal([]byte(`{"message_id":1,"date":1700000000,"edit_date":1700000060,"chat":{"id":1,"type":"private"}}`), &message))
+	require.Equal(t, time.Unix(1700000000, 0), message.Date.Time())
+	require.Equal(t, time.Minute, message.EditDate.Time().Sub(message.Date.Time()))
+	require.True(t, message.ForwardDate.IsZero())
+	require.True(t, message.ForwardDate.Time().IsZero())
+	require.Equal(t, "2023-11-14T22:13:20Z", message.Date.String())
+
+	data, err := json.Marshal(message)
+	require.Nil(t, err)
+	require.Contains(t, string(data), `"date":1700000000,`)
+	require.Contains(t, string(data), `"edit_date":1700000060`)
+	require.NotContains(t, string(data), `forward_date`)
+
+	require.Equal(t, UnixTime(1700000000), UnixTimeFromTime(time.Unix(1700000000, 500)))
+	require.Equal(t, UnixTime(0), UnixTimeFromTime(time.Time{}))
+}
+
+func TestSeconds(t *testing.T) {
+	require.Equal(t, Seconds(90), SecondsFromDuration(90*time.Second+500*time.Millisecond))
+	require.Equal(t, 90*time.Second, Seconds(90).Duration())
+
+	values, err := structToValues(stdJSONCodec{}, sendVideoParams{
+		ChatID: "1",
+		Video:  "v",
+		SendVideoOptions: SendVideoOptions{
+			Duration: SecondsFromDuration(time.Minute),
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "60", values.Get("duration"))
+
+	data, err := json.Marshal(SendLocationOptions{LivePeriod: SecondsFromDuration(time.Hour)})
+	require.Nil(t, err)
+	require.JSONEq(t, `{"live_period":3600}`, string(data))
+}
@@ -1,9 +1,10 @@
 package micha
 
 const (
-	PARSE_MODE_DEFAULT  ParseMode = ""
-	PARSE_MODE_HTML     ParseMode = "HTML"
-	PARSE_MODE_MARKDOWN ParseMode = "Markdown"
+	PARSE_MODE_DEFAULT     ParseMode = ""
+	PARSE_MODE_HTML        ParseMode = "HTML"
+	PARSE_MODE_MARKDOWN    ParseMode = "Markdown" // Legacy mode, use PARSE_MODE_MARKDOWN_V2
+	PARSE_MODE_MARKDOWN_V2 ParseMode = "MarkdownV2"
 
 	CHAT_TYPE_PRIVATE    ChatType = "private"
 	CHAT_TYPE_GROUP      ChatType = "group"
@@ -27,17 +28,28 @@ const (
 	MEMBER_STATUS_LEFT          MemberStatus = "left"
 	MEMBER_STATUS_KICKED        MemberStatus = "kicked"
 
-	MESSAGE_ENTITY_MENTION      MessageEntityType = "mention"
-	MESSAGE_ENTITY_HASHTAG      MessageEntityType = "hashtag"
-	MESSAGE_ENTITY_BOT_COMMAND  MessageEntityType = "bot_command"
-	MESSAGE_ENTITY_URL          MessageEntityType = "url"
-	MESSAGE_ENTITY_EMAIL        MessageEntityType = "email"
-	MESSAGE_ENTITY_BOLD         MessageEntityType = "bold"
-	MESSAGE_ENTITY_ITALIC       MessageEntityType = "italic"
-	MESSAGE_ENTITY_CODE         MessageEntityType = "code"
-	MESSAGE_ENTITY_PRE          MessageEntityType = "pre"
-	MESSAGE_ENTITY_TEXT_LINK    MessageEntityType = "text_link"
-	MESSAGE_ENTITY_TEXT_MENTION MessageEntityType = "text_mention"
+	MESSAGE_ENTITY_MENTION               MessageEntityType = "mention"
+	MESSAGE_ENTITY_HASHTAG               MessageEntityType = "hashtag"
+	MESSAGE_ENTITY_BOT_COMMAND           MessageEntityType = "bot_command"
+	MESSAGE_ENTITY_URL                   MessageEntityType = "url"
+	MESSAGE_ENTITY_EMAIL                 MessageEntityType = "email"
+	MESSAGE_ENTITY_BOLD                  MessageEntityType = "bold"
+	MESSAGE_ENTITY_ITALIC                MessageEntityType = "italic"
+	MESSAGE_ENTITY_CODE                  MessageEntityType = "code"
+	MESSAGE_ENTITY_PRE                   MessageEntityType = "pre"
+	MESSAGE_ENTITY_TEXT_LINK             MessageEntityType = "text_link"
+	MESSAGE_ENTITY_TEXT_MENTION          MessageEntityType = "text_mention"
+	MESSAGE_ENTITY_CASHTAG               MessageEntityType = "cashtag"
+	MESSAGE_ENTITY_PHONE_NUMBER          MessageEntityType = "phone_number"
+	MESSAGE_ENTITY_UNDERLINE             MessageEntityType = "underline"
+	MESSAGE_ENTITY_STRIKETHROUGH         MessageEntityType = "strikethrough"
+	MESSAGE_ENTITY_SPOILER               MessageEntityType = "spoiler"
+	MESSAGE_ENTITY_BLOCKQUOTE            MessageEntityType = "blockquote"
+	MESSAGE_ENTITY_EXPANDABLE_BLOCKQUOTE MessageEntityType = "expandable_blockquote"
+	MESSAGE_ENTITY_CUSTOM_EMOJI          MessageEntityType = "custom_emoji"
+
+	POLL_TYPE_REGULAR PollType = "regular"
+	POLL_TYPE_QUIZ    PollType = "quiz"
 )
 
 type ParseMode string
@@ -45,6 +57,7 @@ type ChatType string
 type ChatAction string
 type MemberStatus string
 type MessageEntityType string
+type PollType string
 
 // User object represents a Telegram user, bot
 type User struct {
@@ -56,13 +69,6 @@ type User struct {
 	LanguageCode string `json:"language_code"`
 }
 
-type ChatID string
-
-func (chatID *ChatID) UnmarshalJSON(value []byte) error {
-	*chatID = ChatID(value)
-	return nil
-}
-
 // Chat object represents a chat.
 type Chat struct {
 	ID   ChatID   `json:"id"`
@@ -80,24 +86,29 @@ type Chat struct {
 	Permissions      *ChatPermissions `json:"permissions,omitempty"`
 	StickerSetName   string           `json:"sticker_set_name,omitempty"`
 	CanSetStickerSet bool             `json:"can_set_sticker_set,omitempty"`
+
+	AvailableReactions []ReactionType `json:"available_reactions,omitempty"` // If omitted, then all emoji reactions are allowed
 }
 
 // Message object represents a message.
 type Message struct {
-	MessageID int64  `json:"message_id"`
-	From      User   `json:"from"`
-	Date      uint64 `json:"date"`
-	Chat      Chat   `json:"chat"`
+	MessageID int64    `json:"message_id"`
+	Date      UnixTime `json:"date"`
+	Chat      Chat     `json:"chat"`
 
 	// Optional
+	From                  *User                `json:"from,omitempty"`        // Empty for messages sent to channels
+	SenderChat            *Chat                `json:"sender_chat,omitempty"` // Sender of the message when sent on behalf of a chat: the channel itself for channel posts, the group for anonymous administrators
+	MessageThreadID       int64                `json:"message_thread_id,omitempty"`
+	IsTopicMessage        bool                 `json:"is_topic_message,omitempty"`
 	ForwardFrom           *User                `json:"forward_from,omitempty"`
 	ForwardFromChat       *Chat                `json:"forward_from_chat,omitempty"`
 	ForwardFromMessageID  int64                `json:"forward_from_message_id,omitempty"`
 	ForwardSignature      string               `json:"forward_signature,omitempty"`
 	ForwardSenderName     string               `json:"forward_sender_name,omitempty"`
-	ForwardDate           uint64               `json:"forward_date,omitempty"`
+	ForwardDate           UnixTime             `json:"forward_date,omitempty"`
 	ReplyToMessage        *Message             `json:"reply_to_message,omitempty"`
-	EditDate              uint64               `json:"edit_date,omitempty"`
+	EditDate              UnixTime             `json:"edit_date,omitempty"`
 	MediaGroupID          string               `json:"media_group_id,omitempty"`
 	AuthorSignature       string               `json:"author_signature,omitempty"`
 	Text                  string               `json:"text,omitempty"`
@@ -117,6 +128,8 @@ type Message struct {
 	Location              *Location            `json:"location,omitempty"`
 	Venue                 *Venue               `json:"venue,omitempty"`
 	Poll                  *Poll                `json:"poll,omitempty"`
+	Dice                  *Dice                `json:"dice,omitempty"`
+	PaidMedia             *PaidMediaInfo       `json:"paid_media,omitempty"`
 	NewChatMembers        []User               `json:"new_chat_members,omitempty"`
 	LeftChatMember        *User                `json:"left_chat_member,omitempty"`
 	NewChatTitle          string               `json:"new_chat_title,omitempty"`
@@ -135,15 +148,45 @@ type Message struct {
 	ReplyMarkup           InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
+// MessageID object represents a unique message identifier.
+type MessageID struct {
+	MessageID int64 `json:"message_id"`
+}
+
 // MessageEntity object represents one special entity in a text message. For example, hashtags, usernames, URLs, etc.
+// Offset and Length are measured in UTF-16 code units.
 type MessageEntity struct {
 	Type   MessageEntityType `json:"type"`
 	Offset int               `json:"offset"`
-	Limit  int               `json:"limit"`
+	Length int               `json:"length"`
 
 	// Optional
-	URL  string `json:"url,omitempty"`  // For “text_link” only, url that will be opened after user taps on the text
-	User *User  `json:"user,omitempty"` // For “text_mention” only, the mentioned user
+	URL           string `json:"url,omitempty"`             // For “text_link” only, url that will be opened after user taps on the text
+	User          *User  `json:"user,omitempty"`            // For “text_mention” only, the mentioned user
+	Language      string `json:"language,omitempty"`        // For “pre” only, the programming language of the entity text
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"` // For “custom_emoji” only, unique identifier of the custom emoji
+}
+
+// ReplyParameters describes reply parameters for the message that is being sent.
+type ReplyParameters struct {
+	MessageID int64 `json:"message_id"`
+
+	// Optional
+	ChatID                   ChatID          `json:"chat_id,omitempty"` // If the message to be replied to is from a different chat
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	Quote                    string          `json:"quote,omitempty"` // Quoted part of the message to be replied to
+	QuoteParseMode           ParseMode       `json:"quote_parse_mode,omitempty"`
+	QuoteEntities            []MessageEntity `json:"quote_entities,omitempty"`
+	QuotePosition            int             `json:"quote_position,omitempty"`
+}
+
+// LinkPreviewOptions describes the options used for link preview generation.
+type LinkPreviewOptions struct {
+	IsDisabled       bool   `json:"is_disabled,omitempty"`
+	URL              string `json:"url,omitempty"`
+	PreferSmallMedia bool   `json:"prefer_small_media,omitempty"`
+	PreferLargeMedia bool   `json:"prefer_large_media,omitempty"`
+	ShowAboveText    bool   `json:"show_above_text,omitempty"`
 }
 
 // PhotoSize object represents an image/sticker of a particular size.
@@ -158,8 +201,8 @@ type PhotoSize struct {
 
 // Audio object represents an audio file (voice note).
 type Audio struct {
-	FileID   string `json:"file_id"`
-	Duration int    `json:"duration"`
+	FileID   string  `json:"file_id"`
+	Duration Seconds `json:"duration"`
 
 	// Optional
 	Performer string     `json:"performer,omitempty"`
@@ -183,10 +226,10 @@ type Document struct {
 
 // Video object represents an MP4-encoded video.
 type Video struct {
-	FileID   string `json:"file_id"`
-	Width    int    `json:"width"`
-	Height   int    `json:"height"`
-	Duration int    `json:"duration"`
+	FileID   string  `json:"file_id"`
+	Width    int     `json:"width"`
+	Height   int     `json:"height"`
+	Duration Seconds `json:"duration"`
 
 	// Optional
 	Thumb    *PhotoSize `json:"thumb,omitempty"`
@@ -196,10 +239,10 @@ type Video struct {
 
 // Animation object represents an animation file (GIF or H.264/MPEG-4 AVC video without sound).
 type Animation struct {
-	FileID   string `json:"file_id"`
-	Width    int    `json:"width"`
-	Height   int    `json:"height"`
-	Duration int    `json:"duration"`
+	FileID   string  `json:"file_id"`
+	Width    int     `json:"width"`
+	Height   int     `json:"height"`
+	Duration Seconds `json:"duration"`
 
 	// Optional
 	Thumb    *PhotoSize `json:"thumb,omitempty"`
@@ -210,8 +253,8 @@ type Animation struct {
 
 // Voice object represents a voice note.
 type Voice struct {
-	FileID   string `json:"file_id"`
-	Duration int    `json:"duration"`
+	FileID   string  `json:"file_id"`
+	Duration Seconds `json:"duration"`
 
 	// Optional
 	MimeType string `json:"mime_type,omitempty"`
@@ -220,9 +263,9 @@ type Voice struct {
 
 // VideoNote object represents a video message.
 type VideoNote struct {
-	FileID   string `json:"file_id"`
-	Length   int    `json:"length"`
-	Duration int    `json:"duration"`
+	FileID   string  `json:"file_id"`
+	Length   int     `json:"length"`
+	Duration Seconds `json:"duration"`
 
 	// Optional
 	Thumb    *PhotoSize `json:"thumb,omitempty"`
@@ -261,14 +304,49 @@ type Venue struct {
 type PollOption struct {
 	Text       string `json:"text"`
 	VoterCount int    `json:"voter_count"`
+
+	// Optional
+	TextEntities []MessageEntity `json:"text_entities,omitempty"`
+}
+
+// InputPollOption object contains information about one answer option in a poll to be sent.
+type InputPollOption struct {
+	Text string `json:"text"`
+
+	// Optional
+	TextParseMode ParseMode       `json:"text_parse_mode,omitempty"`
+	TextEntities  []MessageEntity `json:"text_entities,omitempty"`
 }
 
 // Poll object contains information about a poll.
 type Poll struct {
-	ID       string       `json:"id"`
-	Question string       `json:"question"`
-	Options  []PollOption `json:"options"`
-	IsClosed bool         `json:"is_closed"`
+	ID                    string       `json:"id"`
+	Question              string       `json:"question"`
+	Options               []PollOption `json:"options"`
+	TotalVoterCount       int          `json:"total_voter_count"`
+	IsClosed              bool         `json:"is_closed"`
+	IsAnonymous           bool         `json:"is_anonymous"`
+	Type                  PollType     `json:"type"`
+	AllowsMultipleAnswers bool         `json:"allows_multiple_answers"`
+
+	// Optional
+	QuestionEntities    []MessageEntity `json:"question_entities,omitempty"`
+	CorrectOptionID     *int            `json:"correct_option_id,omitempty"` // Quiz only, available for closed polls or polls sent by the bot
+	Explanation         string          `json:"explanation,omitempty"`
+	ExplanationEntities []MessageEntity `json:"explanation_entities,omitempty"`
+	OpenPeriod          Seconds         `json:"open_period,omitempty"`
+	CloseDate           UnixTime        `json:"close_date,omitempty"`
+}
+
+// PollAnswer object represents an answer of a user in a non-anonymous poll.
+// Empty OptionIDs means the user retracted their vote.
+type PollAnswer struct {
+	PollID    string `json:"poll_id"`
+	OptionIDs []int  `json:"option_ids"`
+
+	// Optional
+	VoterChat *Chat `json:"voter_chat,omitempty"` // The chat that changed the answer to the poll, if the voter is anonymous
+	User      *User `json:"user,omitempty"`       // The user that changed the answer to the poll, if the voter isn't anonymous
 }
 
 // UserProfilePhotos object represent a user's profile pictures.
@@ -304,8 +382,76 @@ type KeyboardButton struct {
 	Text string `json:"text"`
 
 	// Optional
-	RequestContact  bool `json:"request_contact,omitempty"`
-	RequestLocation bool `json:"request_location,omitempty"`
+	RequestUsers    *KeyboardButtonRequestUsers `json:"request_users,omitempty"`
+	RequestChat     *KeyboardButtonRequestChat  `json:"request_chat,omitempty"`
+	RequestContact  bool                        `json:"request_contact,omitempty"`
+	RequestLocation bool                        `json:"request_location,omitempty"`
+	RequestPoll     *KeyboardButtonPollType     `json:"request_poll,omitempty"`
+	WebApp          *WebAppInfo                 `json:"web_app,omitempty"`
+}
+
+// KeyboardButtonRequestUsers object defines the criteria used to request suitable users.
+// Information about the selected users will be shared with the bot when the corresponding button is pressed.
+type KeyboardButtonRequestUsers struct {
+	RequestID int `json:"request_id"`
+
+	// Optional
+	UserIsBot       *bool `json:"user_is_bot,omitempty"`
+	UserIsPremium   *bool `json:"user_is_premium,omitempty"`
+	MaxQuantity     int   `json:"max_quantity,omitempty"`
+	RequestName     bool  `json:"request_name,omitempty"`
+	RequestUsername bool  `json:"request_username,omitempty"`
+	RequestPhoto    bool  `json:"request_photo,omitempty"`
+}
+
+// KeyboardButtonRequestChat object defines the criteria used to request a suitable chat.
+// Information about the selected chat will be shared with the bot when the corresponding button is pressed.
+type KeyboardButtonRequestChat struct {
+	RequestID     int  `json:"request_id"`
+	ChatIsChannel bool `json:"chat_is_channel"`
+
+	// Optional
+	ChatIsForum             *bool                    `json:"chat_is_forum,omitempty"`
+	ChatHasUsername         *bool                    `json:"chat_has_username,omitempty"`
+	ChatIsCreated           bool                     `json:"chat_is_created,omitempty"`
+	UserAdministratorRights *ChatAdministratorRights `json:"user_administrator_rights,omitempty"`
+	BotAdministratorRights  *ChatAdministratorRights `json:"bot_administrator_rights,omitempty"`
+	BotIsMember             bool                     `json:"bot_is_member,omitempty"`
+	RequestTitle            bool                     `json:"request_title,omitempty"`
+	RequestUsername         bool                     `json:"request_username,omitempty"`
+	RequestPhoto            bool                     `json:"request_photo,omitempty"`
+}
+
+// ChatAdministratorRights object represents the rights of an administrator in a chat.
+type ChatAdministratorRights struct {
+	IsAnonymous         bool `json:"is_anonymous"`
+	CanManageChat       bool `json:"can_manage_chat"`
+	CanDeleteMessages   bool `json:"can_delete_messages"`
+	CanManageVideoChats bool `json:"can_manage_video_chats"`
+	CanRestrictMembers  bool `json:"can_restrict_members"`
+	CanPromoteMembers   bool `json:"can_promote_members"`
+	CanChangeInfo       bool `json:"can_change_info"`
+	CanInviteUsers      bool `json:"can_invite_users"`
+	CanPostStories      bool `json:"can_post_stories"`
+	CanEditStories      bool `json:"can_edit_stories"`
+	CanDeleteStories    bool `json:"can_delete_stories"`
+
+	// Optional
+	CanPostMessages bool `json:"can_post_messages,omitempty"`
+	CanEditMessages bool `json:"can_edit_messages,omitempty"`
+	CanPinMessages  bool `json:"can_pin_messages,omitempty"`
+	CanManageTopics bool `json:"can_manage_topics,omitempty"`
+}
+
+// KeyboardButtonPollType object represents type of a poll, which is allowed to be created and sent when the corresponding button is pressed.
+// If Type is empty, any poll type is allowed.
+type KeyboardButtonPollType struct {
+	Type PollType `json:"type,omitempty"`
+}
+
+// WebAppInfo describes a Web App.
+type WebAppInfo struct {
+	URL string `json:"url"`
 }
 
 // ReplyKeyboardMarkup object represents a custom keyboard with reply options
@@ -315,6 +461,9 @@ type ReplyKeyboardMarkup struct {
 	ResizeKeyboard  bool               `json:"resize_keyboard,omitempty"`
 	OneTimeKeyboard bool               `json:"one_time_keyboard,omitempty"`
 	Selective       bool               `json:"selective,omitempty"`
+
+	IsPersistent          bool   `json:"is_persistent,omitempty"`
+	InputFieldPlaceholder string `json:"input_field_placeholder,omitempty"`
 }
 
 // ReplyKeyboardRemove object
@@ -332,12 +481,36 @@ type InlineKeyboardButton struct {
 	Text string `json:"text,omitempty"`
 
 	// Optional
-	URL                          string    `json:"url,omitempty"`
-	LoginURL                     *LoginURL `json:"login_url,omitempty"`
-	CallbackData                 string    `json:"callback_data,omitempty"`
-	SwitchInlineQuery            string    `json:"switch_inline_query,omitempty"`
-	SwitchInlineQueryCurrentChat string    `json:"switch_inline_query_current_chat,omitempty"`
-	Pay                          bool      `json:"pay,omitempty"`
+	URL                          string                       `json:"url,omitempty"`
+	LoginURL                     *LoginURL                    `json:"login_url,omitempty"`
+	CallbackData                 string                       `json:"callback_data,omitempty"`
+	WebApp                       *WebAppInfo                  `json:"web_app,omitempty"`
+	SwitchInlineQuery            string                       `json:"switch_inline_query,omitempty"`              // Empty query is sent only by buttons created with NewInlineButtonSwitchInline
+	SwitchInlineQueryCurrentChat string                       `json:"switch_inline_query_current_chat,omitempty"` // Empty query is sent only by buttons created with NewInlineButtonSwitchInlineCurrentChat
+	SwitchInlineQueryChosenChat  *SwitchInlineQueryChosenChat `json:"switch_inline_query_chosen_chat,omitempty"`
+	CopyText                     *CopyTextButton              `json:"copy_text,omitempty"`
+	CallbackGame                 *CallbackGame                `json:"callback_game,omitempty"`
+	Pay                          bool                         `json:"pay,omitempty"`
+
+	// Switch inline query fields are set to empty query
+	emptySwitchInlineQuery            bool
+	emptySwitchInlineQueryCurrentChat bool
+}
+
+// SwitchInlineQueryChosenChat object represents an inline button that switches the current user to inline mode in a chosen chat,
+// with an optional default inline query.
+type SwitchInlineQueryChosenChat struct {
+	// Optional
+	Query             string `json:"query,omitempty"`
+	AllowUserChats    bool   `json:"allow_user_chats,omitempty"`
+	AllowBotChats     bool   `json:"allow_bot_chats,omitempty"`
+	AllowGroupChats   bool   `json:"allow_group_chats,omitempty"`
+	AllowChannelChats bool   `json:"allow_channel_chats,omitempty"`
+}
+
+// CopyTextButton object represents an inline keyboard button that copies specified text to the clipboard.
+type CopyTextButton struct {
+	Text string `json:"text"`
 }
 
 // LoginURL object represents a parameter of the inline keyboard button used to automatically authorize a user.
@@ -404,22 +577,22 @@ type ChatMember struct {
 	Status MemberStatus `json:"status"`
 
 	// Optional
-	UntilDate             int64 `json:"until_date,omitempty"`
-	CanBeEdited           bool  `json:"can_be_edited,omitempty"`
-	CanPostMessages       bool  `json:"can_post_messages,omitempty"`
-	CanEditMessages       bool  `json:"can_edit_messages,omitempty"`
-	CanDeleteMessages     bool  `json:"can_delete_messages,omitempty"`
-	CanRestrictMembers    bool  `json:"can_restrict_members,omitempty"`
-	CanPromoteMembers     bool  `json:"can_promote_members,omitempty"`
-	CanChangeInfo         bool  `json:"can_change_info,omitempty"`
-	CanInviteUsers        bool  `json:"can_invite_users,omitempty"`
-	CanPinMessages        bool  `json:"can_pin_messages,omitempty"`
-	IsMember              bool  `json:"is_member,omitempty"`
-	CanSendMessages       bool  `json:"can_send_messages,omitempty"`
-	CanSendMediaMessages  bool  `json:"can_send_media_messages,omitempty"`
-	CanSendPolls          bool  `json:"can_send_polls,omitempty"`
-	CanSendOtherMessages  bool  `json:"can_send_other_messages,omitempty"`
-	CanAddWebPagePreviews bool  `json:"can_add_web_page_previews,omitempty"`
+	UntilDate             UnixTime `json:"until_date,omitempty"`
+	CanBeEdited           bool     `json:"can_be_edited,omitempty"`
+	CanPostMessages       bool     `json:"can_post_messages,omitempty"`
+	CanEditMessages       bool     `json:"can_edit_messages,omitempty"`
+	CanDeleteMessages     bool     `json:"can_delete_messages,omitempty"`
+	CanRestrictMembers    bool     `json:"can_restrict_members,omitempty"`
+	CanPromoteMembers     bool     `json:"can_promote_members,omitempty"`
+	CanChangeInfo         bool     `json:"can_change_info,omitempty"`
+	CanInviteUsers        bool     `json:"can_invite_users,omitempty"`
+	CanPinMessages        bool     `json:"can_pin_messages,omitempty"`
+	IsMember              bool     `json:"is_member,omitempty"`
+	CanSendMessages       bool     `json:"can_send_messages,omitempty"`
+	CanSendMediaMessages  bool     `json:"can_send_media_messages,omitempty"`
+	CanSendPolls          bool     `json:"can_send_polls,omitempty"`
+	CanSendOtherMessages  bool     `json:"can_send_other_messages,omitempty"`
+	CanAddWebPagePreviews bool     `json:"can_add_web_page_previews,omitempty"`
 }
 
 // ChatPermissions describes actions that a non-administrator user is allowed to take in a chat.
@@ -456,6 +629,10 @@ type Update struct {
 	ShippingQuery      *ShippingQuery      `json:"shipping_query,omitempty"`
 	PreCheckoutQuery   *PreCheckoutQuery   `json:"pre_checkout_query,omitempty"`
 	Poll               *Poll               `json:"poll,omitempty"`
+	PollAnswer         *PollAnswer         `json:"poll_answer,omitempty"`
+
+	MessageReaction      *MessageReactionUpdated      `json:"message_reaction,omitempty"`
+	MessageReactionCount *MessageReactionCountUpdated `json:"message_reaction_count,omitempty"`
 }
 
 // WebhookInfo contains information about the current status of a webhook.
@@ -463,7 +640,7 @@ type WebhookInfo struct {
 	URL                  string   `json:"url"`
 	HasCustomCertificate bool     `json:"has_custom_certificate"`
 	PendingUpdateCount   int      `json:"pending_update_count"`
-	LastErrorDate        uint64   `json:"last_error_date,omitempty"`
+	LastErrorDate        UnixTime `json:"last_error_date,omitempty"`
 	LastErrorMessage     string   `json:"last_error_message,omitempty"`
 	MaxConnections       int      `json:"max_connections,omitempty"`
 	AllowedUpdates       []string `json:"allowed_updates,omitempty"`
@@ -19,3 +19,6 @@ type GameHighScore struct {
 	User     User `json:"user"`
 	Score    int  `json:"score"`
 }
+
+// CallbackGame is a placeholder, currently holds no information. Use BotFather to set up your game.
+type CallbackGame struct{}
@@ -14,6 +14,10 @@ const (
 	INLINE_TYPE_RESULT_CONTACT   InlineResultType = "contact"
 	INLINE_TYPE_RESULT_STICKER   InlineResultType = "sticker"
 	INLINE_TYPE_RESULT_GAME      InlineResultType = "game"
+
+	THUMBNAIL_MIME_TYPE_JPEG ThumbnailMimeType = "image/jpeg"
+	THUMBNAIL_MIME_TYPE_GIF  ThumbnailMimeType = "image/gif"
+	THUMBNAIL_MIME_TYPE_MP4  ThumbnailMimeType = "video/mp4"
 )
 
 // InlineQuery object represents an incoming inline query.
@@ -27,6 +31,17 @@ type InlineQuery struct {
 }
 
 type InlineResultType string
+type ThumbnailMimeType string
+
+// InlineQueryResultsButton represents a button to be shown above inline query results.
+// You must use exactly one of the optional fields.
+type InlineQueryResultsButton struct {
+	Text string `json:"text"`
+
+	// Optional
+	WebApp         *WebAppInfo `json:"web_app,omitempty"`
+	StartParameter string      `json:"start_parameter,omitempty"` // Deep-linking parameter for the /start message, 1-64 characters
+}
 
 type InlineQueryResults []InlineQueryResult
 
@@ -41,19 +56,19 @@ func (i inlineQueryResultImplementation) itsInlineQueryResult() {}
 // Represents a link to an article or web page.
 type InlineQueryResultArticle struct {
 	inlineQueryResultImplementation
-	Type  InlineResultType `json:"type"`
-	ID    string           `json:"id"`
-	Title string           `json:"title"`
+	Type                InlineResultType    `json:"type"`
+	ID                  string              `json:"id"`
+	Title               string              `json:"title"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 
 	// Optional
-	URL                 string                `json:"url,omitempty"`
-	HideURL             bool                  `json:"hide_url,omitempty"`
-	Description         string                `json:"description,omitempty"`
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	ThumbWidth          int                   `json:"thumb_width,omitempty"`
-	ThumbHeight         int                   `json:"thumb_height,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ReplyMarkup     *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	URL             string                `json:"url,omitempty"`
+	HideURL         bool                  `json:"hide_url,omitempty"` // Deprecated: leave URL empty instead
+	Description     string                `json:"description,omitempty"`
+	ThumbnailURL    string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight int                   `json:"thumbnail_height,omitempty"`
 }
 
 // Represents a link to a photo.
@@ -61,20 +76,22 @@ type InlineQueryResultArticle struct {
 // Alternatively, you can use input_message_content to send a message with the specified content instead of the photo.
 type InlineQueryResultPhoto struct {
 	inlineQueryResultImplementation
-	Type     InlineResultType `json:"type"`
-	ID       string           `json:"id"`
-	PhotoURL string           `json:"photo_url"`
+	Type         InlineResultType `json:"type"`
+	ID           string           `json:"id"`
+	PhotoURL     string           `json:"photo_url"`
+	ThumbnailURL string           `json:"thumbnail_url"`
 
 	// Optional
-	MimeType            string                `json:"mime_type,omitempty"`
-	PhotoWidth          int                   `json:"photo_width,omitempty"`
-	PhotoHeight         int                   `json:"photo_height,omitempty"`
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	Title               string                `json:"title,omitempty"`
-	Description         string                `json:"description,omitempty"`
-	Caption             string                `json:"caption,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	PhotoWidth            int                   `json:"photo_width,omitempty"`
+	PhotoHeight           int                   `json:"photo_height,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a link to a photo stored on the Telegram servers.
@@ -87,11 +104,14 @@ type InlineQueryResultCachedPhoto struct {
 	PhotoFileID string           `json:"photo_file_id"`
 
 	// Optional
-	Title               string                `json:"title,omitempty"`
-	Description         string                `json:"description,omitempty"`
-	Caption             string                `json:"caption,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a link to an animated GIF file.
@@ -99,19 +119,23 @@ type InlineQueryResultCachedPhoto struct {
 // Alternatively, you can use input_message_content to send a message with the specified content instead of the animation.
 type InlineQueryResultGif struct {
 	inlineQueryResultImplementation
-	Type   InlineResultType `json:"type"`
-	ID     string           `json:"id"`
-	GifURL string           `json:"gif_url"`
+	Type         InlineResultType `json:"type"`
+	ID           string           `json:"id"`
+	GifURL       string           `json:"gif_url"`
+	ThumbnailURL string           `json:"thumbnail_url"`
 
 	// Optional
-	GifWidth            int                   `json:"gif_width,omitempty"`
-	GifHeight           int                   `json:"gif_height,omitempty"`
-	GifDuration         int                   `json:"gif_duration"`
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	Title               string                `json:"title,omitempty"`
-	Caption             string                `json:"caption,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	GifWidth              int                   `json:"gif_width,omitempty"`
+	GifHeight             int                   `json:"gif_height,omitempty"`
+	GifDuration           Seconds               `json:"gif_duration,omitempty"`
+	ThumbnailMimeType     ThumbnailMimeType     `json:"thumbnail_mime_type,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a link to an animated GIF file stored on the Telegram servers.
@@ -124,10 +148,13 @@ type InlineQueryResultCachedGif struct {
 	GifFileID string           `json:"gif_file_id"`
 
 	// Optional
-	Title               string                `json:"title,omitempty"`
-	Caption             string                `json:"caption,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a link to a video animation (H.264/MPEG-4 AVC video without sound).
@@ -135,19 +162,23 @@ type InlineQueryResultCachedGif struct {
 // Alternatively, you can use input_message_content to send a message with the specified content instead of the animation.
 type InlineQueryResultMpeg4Gif struct {
 	inlineQueryResultImplementation
-	Type     InlineResultType `json:"type"`
-	ID       string           `json:"id"`
-	Mpeg4URL string           `json:"mpeg4_url"`
+	Type         InlineResultType `json:"type"`
+	ID           string           `json:"id"`
+	Mpeg4URL     string           `json:"mpeg4_url"`
+	ThumbnailURL string           `json:"thumbnail_url"`
 
 	// Optional
-	Mpeg4Width          int                   `json:"mpeg4_width,omitempty"`
-	Mpeg4Height         int                   `json:"mpeg4_height,omitempty"`
-	Mpeg4Duration       int                   `json:"mpeg4_duration,omitempty"`
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	Title               string                `json:"title,omitempty"`
-	Caption             string                `json:"caption,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	Mpeg4Width            int                   `json:"mpeg4_width,omitempty"`
+	Mpeg4Height           int                   `json:"mpeg4_height,omitempty"`
+	Mpeg4Duration         Seconds               `json:"mpeg4_duration,omitempty"`
+	ThumbnailMimeType     ThumbnailMimeType     `json:"thumbnail_mime_type,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a link to a video animation (H.264/MPEG-4 AVC video without sound) stored on the Telegram servers.
@@ -160,10 +191,13 @@ type InlineQueryResultCachedMpeg4Gif struct {
 	Mpeg4FileID string           `json:"mpeg4_file_id"`
 
 	// Optional
-	Title               string                `json:"title,omitempty"`
-	Caption             string                `json:"caption,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	Title                 string                `json:"title,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a link to a page containing an embedded video player or a video file.
@@ -171,21 +205,24 @@ type InlineQueryResultCachedMpeg4Gif struct {
 // Alternatively, you can use input_message_content to send a message with the specified content instead of the video.
 type InlineQueryResultVideo struct {
 	inlineQueryResultImplementation
-	Type     InlineResultType `json:"type"`
-	ID       string           `json:"id"`
-	VideoURL string           `json:"video_url"`
-	MimeType string           `json:"mime_type"`
+	Type         InlineResultType `json:"type"`
+	ID           string           `json:"id"`
+	VideoURL     string           `json:"video_url"`
+	MimeType     string           `json:"mime_type"`
+	ThumbnailURL string           `json:"thumbnail_url"`
+	Title        string           `json:"title"`
 
 	// Optional
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	Title               string                `json:"title,omitempty"`
-	Caption             string                `json:"caption,omitempty"`
-	VideoWidth          int                   `json:"video_width,omitempty"`
-	VideoHeight         int                   `json:"video_height,omitempty"`
-	VideoDuration       int                   `json:"video_duration,omitempty"`
-	Description         string                `json:"description,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	VideoWidth            int                   `json:"video_width,omitempty"`
+	VideoHeight           int                   `json:"video_height,omitempty"`
+	VideoDuration         Seconds               `json:"video_duration,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a link to a video file stored on the Telegram servers.
@@ -196,13 +233,16 @@ type InlineQueryResultCachedVideo struct {
 	Type        InlineResultType `json:"type"`
 	ID          string           `json:"id"`
 	VideoFileID string           `json:"video_file_id"`
+	Title       string           `json:"title"`
 
 	// Optional
-	Title               string                `json:"title,omitempty"`
-	Description         string                `json:"description,omitempty"`
-	Caption             string                `json:"caption,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	ParseMode             ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool                  `json:"show_caption_above_media,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent   InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a link to an mp3 audio file.
@@ -216,8 +256,11 @@ type InlineQueryResultAudio struct {
 	Title    string           `json:"title"`
 
 	// Optional
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	Performer           string                `json:"performer,omitempty"`
-	AudioDuration       int                   `json:"audio_duration,omitempty"`
+	AudioDuration       Seconds               `json:"audio_duration,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
 }
@@ -232,6 +275,9 @@ type InlineQueryResultCachedAudio struct {
 	AudioFileID string           `json:"audio_file_id"`
 
 	// Optional
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
 }
@@ -247,7 +293,10 @@ type InlineQueryResultVoice struct {
 	Title    string           `json:"title"`
 
 	// Optional
-	VoiceDuration       int                   `json:"voice_duration,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	VoiceDuration       Seconds               `json:"voice_duration,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
 }
@@ -260,9 +309,12 @@ type InlineQueryResultCachedVoice struct {
 	Type        InlineResultType `json:"type"`
 	ID          string           `json:"id"`
 	VoiceFileID string           `json:"voice_file_id"`
+	Title       string           `json:"title"`
 
 	// Optional
-	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
 }
@@ -280,10 +332,12 @@ type InlineQueryResultDocument struct {
 
 	// Optional
 	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	Description         string                `json:"description,omitempty"`
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	ThumbWidth          int                   `json:"thumb_width,omitempty"`
-	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
 }
@@ -302,6 +356,8 @@ type InlineQueryResultCachedDocument struct {
 	// Optional
 	Description         string                `json:"description,omitempty"`
 	Caption             string                `json:"caption,omitempty"`
+	ParseMode           ParseMode             `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
 }
@@ -318,11 +374,15 @@ type InlineQueryResultLocation struct {
 	Title     string           `json:"title"`
 
 	// Optional
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	ThumbWidth          int                   `json:"thumb_width,omitempty"`
-	ThumbHeight         int                   `json:"thumb_height,omitempty"`
-	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	HorizontalAccuracy   float64               `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           Seconds               `json:"live_period,omitempty"`
+	Heading              int                   `json:"heading,omitempty"`
+	ProximityAlertRadius int                   `json:"proximity_alert_radius,omitempty"`
+	ThumbnailURL         string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth       int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight      int                   `json:"thumbnail_height,omitempty"`
+	ReplyMarkup          *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent  InputMessageContent   `json:"input_message_content,omitempty"`
 }
 
 // Represents a venue.
@@ -339,9 +399,12 @@ type InlineQueryResultVenue struct {
 
 	// Optional
 	FoursquareID        string                `json:"foursquare_id,omitempty"`
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	ThumbWidth          int                   `json:"thumb_width,omitempty"`
-	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+	FoursquareType      string                `json:"foursquare_type,omitempty"`
+	GooglePlaceID       string                `json:"google_place_id,omitempty"`
+	GooglePlaceType     string                `json:"google_place_type,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
 }
@@ -372,9 +435,10 @@ type InlineQueryResultContact struct {
 
 	// Optional
 	LastName            string                `json:"last_name,omitempty"`
-	ThumbURL            string                `json:"thumb_url,omitempty"`
-	ThumbWidth          int                   `json:"thumb_width,omitempty"`
-	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+	VCard               string                `json:"vcard,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
 	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
 }
@@ -401,9 +465,13 @@ func (i inputMessageContentImplementation) itsInputMessageContent() {}
 // InputTextMessageContent contains text for displaying as an inline query result.
 type InputTextMessageContent struct {
 	inputMessageContentImplementation
-	MessageText           string    `json:"message_text"`
-	ParseMode             ParseMode `json:"parse_mode"`
-	DisableWebPagePreview bool      `json:"disable_web_page_preview"`
+	MessageText string `json:"message_text"`
+
+	// Optional
+	ParseMode             ParseMode           `json:"parse_mode,omitempty"`
+	Entities              []MessageEntity     `json:"entities,omitempty"`
+	LinkPreviewOptions    *LinkPreviewOptions `json:"link_preview_options,omitempty"`
+	DisableWebPagePreview bool                `json:"disable_web_page_preview,omitempty"` // Deprecated: use LinkPreviewOptions
 }
 
 // InputLocationMessageContent contains a location for displaying as an inline query result.
@@ -411,16 +479,27 @@ type InputLocationMessageContent struct {
 	inputMessageContentImplementation
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
+
+	// Optional
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           Seconds `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
 }
 
 // InputVenueMessageContent contains a venue for displaying an inline query result.
 type InputVenueMessageContent struct {
 	inputMessageContentImplementation
-	Latitude     float64 `json:"latitude"`
-	Longitude    float64 `json:"longitude"`
-	Title        string  `json:"title"`
-	Address      string  `json:"address"`
-	FoursquareID string  `json:"foursquare_id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Title     string  `json:"title"`
+	Address   string  `json:"address"`
+
+	// Optional
+	FoursquareID    string `json:"foursquare_id,omitempty"`
+	FoursquareType  string `json:"foursquare_type,omitempty"`
+	GooglePlaceID   string `json:"google_place_id,omitempty"`
+	GooglePlaceType string `json:"google_place_type,omitempty"`
 }
 
 // InputContactMessageContent contains a contact for displaying as an inline query result.
@@ -428,5 +507,36 @@ type InputContactMessageContent struct {
 	inputMessageContentImplementation
 	PhoneNumber string `json:"phone_number"`
 	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
+
+	// Optional
+	LastName string `json:"last_name,omitempty"`
+	VCard    string `json:"vcard,omitempty"`
+}
+
+// InputInvoiceMessageContent contains an invoice for displaying as an inline query result.
+// Use currency "XTR" and empty ProviderToken for payments in Telegram Stars.
+type InputInvoiceMessageContent struct {
+	inputMessageContentImplementation
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Payload     string         `json:"payload"`
+	Currency    string         `json:"currency"`
+	Prices      []LabeledPrice `json:"prices"`
+
+	// Optional
+	ProviderToken             string `json:"provider_token,omitempty"`
+	MaxTipAmount              int    `json:"max_tip_amount,omitempty"`
+	SuggestedTipAmounts       []int  `json:"suggested_tip_amounts,omitempty"`
+	ProviderData              string `json:"provider_data,omitempty"`
+	PhotoURL                  string `json:"photo_url,omitempty"`
+	PhotoSize                 int    `json:"photo_size,omitempty"`
+	PhotoWidth                int    `json:"photo_width,omitempty"`
+	PhotoHeight               int    `json:"photo_height,omitempty"`
+	NeedName                  bool   `json:"need_name,omitempty"`
+	NeedPhoneNumber           bool   `json:"need_phone_number,omitempty"`
+	NeedEmail                 bool   `json:"need_email,omitempty"`
+	NeedShippingAddress       bool   `json:"need_shipping_address,omitempty"`
+	SendPhoneNumberToProvider bool   `json:"send_phone_number_to_provider,omitempty"`
+	SendEmailToProvider       bool   `json:"send_email_to_provider,omitempty"`
+	IsFlexible                bool   `json:"is_flexible,omitempty"`
 }
@@ -1,7 +1,11 @@
 package micha
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestInlineQueryResult(t *testing.T) {
@@ -33,3 +37,100 @@ func TestInputMessageContent(t *testing.T) {
 	(InputVenueMessageContent{}).itsInputMessageContent()
 	(inputMessageContentImplementation{}).itsInputMessageContent()
 }
+
+// fillTestValue sets every exported field to a non-zero value, interfaces are left nil
+func fillTestValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Int, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillTestValue(v.Index(0))
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillTestValue(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillTestValue(v.Field(i))
+			}
+		}
+	}
+}
+
+func TestInlineQueryResultRoundTrip(t *testing.T) {
+	for _, value := range []interface{}{
+		&InlineQueryResultArticle{},
+		&InlineQueryResultPhoto{},
+		&InlineQueryResultCachedPhoto{},
+		&InlineQueryResultGif{},
+		&InlineQueryResultCachedGif{},
+		&InlineQueryResultMpeg4Gif{},
+		&InlineQueryResultCachedMpeg4Gif{},
+		&InlineQueryResultVideo{},
+		&InlineQueryResultCachedVideo{},
+		&InlineQueryResultAudio{},
+		&InlineQueryResultCachedAudio{},
+		&InlineQueryResultVoice{},
+		&InlineQueryResultCachedVoice{},
+		&InlineQueryResultDocument{},
+		&InlineQueryResultCachedDocument{},
+		&InlineQueryResultLocation{},
+		&InlineQueryResultVenue{},
+		&InlineQueryResultCachedSticker{},
+		&InlineQueryResultContact{},
+		&InlineQueryResultGame{},
+		&InputTextMessageContent{},
+		&InputLocationMessageContent{},
+		&InputVenueMessageContent{},
+		&InputContactMessageContent{},
+		&InputInvoiceMessageContent{},
+		&InlineQueryResultsButton{},
+	} {
+		fillTestValue(reflect.ValueOf(value).Elem())
+
+		data, err := json.Marshal(value)
+		require.Nil(t, err)
+
+		decoded := reflect.New(reflect.TypeOf(value).Elem()).Interface()
+		require.Nil(t, json.Unmarshal(data, decoded))
+		require.Equal(t, value, decoded, string(data))
+
+		// Every field is serialized with its own key
+		fields := map[string]interface{}{}
+		require.Nil(t, json.Unmarshal(data, &fields))
+		expected := 0
+		for _, field := range reflect.VisibleFields(reflect.TypeOf(value).Elem()) {
+			if field.IsExported() && !field.Anonymous && field.Type.Kind() != reflect.Interface {
+				expected++
+			}
+		}
+		require.Len(t, fields, expected, string(data))
+	}
+}
+
+func TestInlineQueryResultOmitEmpty(t *testing.T) {
+	data, err := json.Marshal(InputTextMessageContent{MessageText: "text"})
+	require.Nil(t, err)
+	require.JSONEq(t, `{"message_text":"text"}`, string(data))
+
+	data, err = json.Marshal(NewInlineQueryResultGif("1", "https://example.com/a.gif", "https://example.com/t.jpg"))
+	require.Nil(t, err)
+	require.JSONEq(t, `{"type":"gif","id":"1","gif_url":"https://example.com/a.gif","thumbnail_url":"https://example.com/t.jpg"}`, string(data))
+
+	data, err = json.Marshal(InputInvoiceMessageContent{
+		Title:       "Title",
+		Description: "Description",
+		Payload:     "payload",
+		Currency:    "XTR",
+		Prices:      []LabeledPrice{{Label: "Item", Amount: 10}},
+	})
+	require.Nil(t, err)
+	require.JSONEq(t, `{"title":"Title","description":"Description","payload":"payload","currency":"XTR","prices":[{"label":"Item","amount":10}]}`, string(data))
+}
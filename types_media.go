@@ -0,0 +1,151 @@
+package micha
+
+const (
+	INPUT_MEDIA_TYPE_PHOTO     InputMediaType = "photo"
+	INPUT_MEDIA_TYPE_VIDEO     InputMediaType = "video"
+	INPUT_MEDIA_TYPE_ANIMATION InputMediaType = "animation"
+	INPUT_MEDIA_TYPE_AUDIO     InputMediaType = "audio"
+	INPUT_MEDIA_TYPE_DOCUMENT  InputMediaType = "document"
+
+	DICE_EMOJI_DICE         DiceEmoji = "🎲"
+	DICE_EMOJI_DARTS        DiceEmoji = "🎯"
+	DICE_EMOJI_BASKETBALL   DiceEmoji = "🏀"
+	DICE_EMOJI_FOOTBALL     DiceEmoji = "⚽"
+	DICE_EMOJI_BOWLING      DiceEmoji = "🎳"
+	DICE_EMOJI_SLOT_MACHINE DiceEmoji = "🎰"
+
+	PAID_MEDIA_TYPE_PREVIEW PaidMediaType = "preview"
+	PAID_MEDIA_TYPE_PHOTO   PaidMediaType = "photo"
+	PAID_MEDIA_TYPE_VIDEO   PaidMediaType = "video"
+)
+
+type InputMediaType string
+type DiceEmoji string
+type PaidMediaType string
+
+// Dice object represents an animated emoji that displays a random value.
+type Dice struct {
+	Emoji DiceEmoji `json:"emoji"`
+	Value int       `json:"value"`
+}
+
+// PaidMedia object describes paid media.
+type PaidMedia struct {
+	Type PaidMediaType `json:"type"`
+
+	// Optional
+	Width    int         `json:"width,omitempty"`    // For “preview” only
+	Height   int         `json:"height,omitempty"`   // For “preview” only
+	Duration Seconds     `json:"duration,omitempty"` // For “preview” only
+	Photo    []PhotoSize `json:"photo,omitempty"`    // For “photo” only
+	Video    *Video      `json:"video,omitempty"`    // For “video” only
+}
+
+// PaidMediaInfo object describes the paid media added to a message.
+type PaidMediaInfo struct {
+	StarCount int         `json:"star_count"`
+	PaidMedia []PaidMedia `json:"paid_media"`
+}
+
+// InputPaidMedia object describes the paid media to be sent.
+// Type must be INPUT_MEDIA_TYPE_PHOTO or INPUT_MEDIA_TYPE_VIDEO.
+type InputPaidMedia struct {
+	Type  InputMediaType `json:"type"`
+	Media string         `json:"media"` // File ID, HTTP URL or "attach://<file_attach_name>"
+
+	// Optional, for video only
+	Thumbnail         string  `json:"thumbnail,omitempty"`
+	Width             int     `json:"width,omitempty"`
+	Height            int     `json:"height,omitempty"`
+	Duration          Seconds `json:"duration,omitempty"`
+	SupportsStreaming bool    `json:"supports_streaming,omitempty"`
+}
+
+type InputMedia interface {
+	itsInputMedia()
+}
+
+type inputMediaImplementation struct{}
+
+func (i inputMediaImplementation) itsInputMedia() {}
+
+// InputMediaPhoto represents a photo to be sent.
+type InputMediaPhoto struct {
+	inputMediaImplementation
+	Type  InputMediaType `json:"type"`
+	Media string         `json:"media"`
+
+	// Optional
+	Caption               string          `json:"caption,omitempty"`
+	ParseMode             ParseMode       `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool            `json:"show_caption_above_media,omitempty"`
+	HasSpoiler            bool            `json:"has_spoiler,omitempty"`
+}
+
+// InputMediaVideo represents a video to be sent.
+type InputMediaVideo struct {
+	inputMediaImplementation
+	Type  InputMediaType `json:"type"`
+	Media string         `json:"media"`
+
+	// Optional
+	Thumbnail             string          `json:"thumbnail,omitempty"`
+	Caption               string          `json:"caption,omitempty"`
+	ParseMode             ParseMode       `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool            `json:"show_caption_above_media,omitempty"`
+	Width                 int             `json:"width,omitempty"`
+	Height                int             `json:"height,omitempty"`
+	Duration              Seconds         `json:"duration,omitempty"`
+	SupportsStreaming     bool            `json:"supports_streaming,omitempty"`
+	HasSpoiler            bool            `json:"has_spoiler,omitempty"`
+}
+
+// InputMediaAnimation represents an animation file (GIF or H.264/MPEG-4 AVC video without sound) to be sent.
+type InputMediaAnimation struct {
+	inputMediaImplementation
+	Type  InputMediaType `json:"type"`
+	Media string         `json:"media"`
+
+	// Optional
+	Thumbnail             string          `json:"thumbnail,omitempty"`
+	Caption               string          `json:"caption,omitempty"`
+	ParseMode             ParseMode       `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool            `json:"show_caption_above_media,omitempty"`
+	Width                 int             `json:"width,omitempty"`
+	Height                int             `json:"height,omitempty"`
+	Duration              Seconds         `json:"duration,omitempty"`
+	HasSpoiler            bool            `json:"has_spoiler,omitempty"`
+}
+
+// InputMediaAudio represents an audio file to be treated as music to be sent.
+type InputMediaAudio struct {
+	inputMediaImplementation
+	Type  InputMediaType `json:"type"`
+	Media string         `json:"media"`
+
+	// Optional
+	Thumbnail       string          `json:"thumbnail,omitempty"`
+	Caption         string          `json:"caption,omitempty"`
+	ParseMode       ParseMode       `json:"parse_mode,omitempty"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+	Duration        Seconds         `json:"duration,omitempty"`
+	Performer       string          `json:"performer,omitempty"`
+	Title           string          `json:"title,omitempty"`
+}
+
+// InputMediaDocument represents a general file to be sent.
+type InputMediaDocument struct {
+	inputMediaImplementation
+	Type  InputMediaType `json:"type"`
+	Media string         `json:"media"`
+
+	// Optional
+	Thumbnail                   string          `json:"thumbnail,omitempty"`
+	Caption                     string          `json:"caption,omitempty"`
+	ParseMode                   ParseMode       `json:"parse_mode,omitempty"`
+	CaptionEntities             []MessageEntity `json:"caption_entities,omitempty"`
+	DisableContentTypeDetection bool            `json:"disable_content_type_detection,omitempty"`
+}
@@ -12,18 +12,52 @@ type SuccessfulPayment struct {
 	// TODO
 }
 
+// ShippingAddress represents a shipping address.
 type ShippingAddress struct {
-	// TODO
+	CountryCode string `json:"country_code"` // Two-letter ISO 3166-1 alpha-2 country code
+	State       string `json:"state"`
+	City        string `json:"city"`
+	StreetLine1 string `json:"street_line1"`
+	StreetLine2 string `json:"street_line2"`
+	PostCode    string `json:"post_code"`
+}
+
+// OrderInfo represents information about an order.
+type OrderInfo struct {
+	// Optional
+	Name            string           `json:"name,omitempty"`
+	PhoneNumber     string           `json:"phone_number,omitempty"`
+	Email           string           `json:"email,omitempty"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
 }
 
 type PassportData struct {
 	// TODO
 }
 
+// ShippingQuery contains information about an incoming shipping query.
 type ShippingQuery struct {
-	// TODO
+	ID              string          `json:"id"`
+	From            User            `json:"from"`
+	InvoicePayload  string          `json:"invoice_payload"`
+	ShippingAddress ShippingAddress `json:"shipping_address"`
 }
 
+// PreCheckoutQuery contains information about an incoming pre-checkout query.
 type PreCheckoutQuery struct {
-	// TODO
+	ID             string `json:"id"`
+	From           User   `json:"from"`
+	Currency       string `json:"currency"`
+	TotalAmount    int    `json:"total_amount"` // Total price in the smallest units of the currency
+	InvoicePayload string `json:"invoice_payload"`
+
+	// Optional
+	ShippingOptionID string     `json:"shipping_option_id,omitempty"`
+	OrderInfo        *OrderInfo `json:"order_info,omitempty"`
+}
+
+// LabeledPrice represents a portion of the price for goods or services.
+type LabeledPrice struct {
+	Label  string `json:"label"`
+	Amount int    `json:"amount"` // Price in the smallest units of the currency
 }
@@ -0,0 +1,88 @@
+package micha
+
+const (
+	REACTION_TYPE_EMOJI        ReactionTypeType = "emoji"
+	REACTION_TYPE_CUSTOM_EMOJI ReactionTypeType = "custom_emoji"
+	REACTION_TYPE_PAID         ReactionTypeType = "paid"
+)
+
+type ReactionTypeType string
+
+// ReactionType object describes the type of a reaction.
+type ReactionType struct {
+	Type ReactionTypeType `json:"type"`
+
+	// Optional
+	Emoji         string `json:"emoji,omitempty"`           // For “emoji” only
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"` // For “custom_emoji” only
+}
+
+// NewEmojiReaction - create reaction based on a predefined emoji
+func NewEmojiReaction(emoji string) ReactionType {
+	return ReactionType{Type: REACTION_TYPE_EMOJI, Emoji: emoji}
+}
+
+// NewCustomEmojiReaction - create reaction based on a custom emoji
+func NewCustomEmojiReaction(customEmojiID string) ReactionType {
+	return ReactionType{Type: REACTION_TYPE_CUSTOM_EMOJI, CustomEmojiID: customEmojiID}
+}
+
+// NewPaidReaction - create paid reaction
+func NewPaidReaction() ReactionType {
+	return ReactionType{Type: REACTION_TYPE_PAID}
+}
+
+// ReactionCount represents a reaction added to a message along with the number of times it was added.
+type ReactionCount struct {
+	Type       ReactionType `json:"type"`
+	TotalCount int          `json:"total_count"`
+}
+
+// MessageReactionUpdated object represents a change of a reaction on a message performed by a user.
+type MessageReactionUpdated struct {
+	Chat        Chat           `json:"chat"`
+	MessageID   int64          `json:"message_id"`
+	Date        UnixTime       `json:"date"`
+	OldReaction []ReactionType `json:"old_reaction"`
+	NewReaction []ReactionType `json:"new_reaction"`
+
+	// Optional
+	User      *User `json:"user,omitempty"`       // The user that changed the reaction, if the user isn't anonymous
+	ActorChat *Chat `json:"actor_chat,omitempty"` // The chat on behalf of which the reaction was changed, if the user is anonymous
+}
+
+// Diff - return reactions added and removed by the change
+func (m MessageReactionUpdated) Diff() (added, removed []ReactionType) {
+	return diffReactions(m.OldReaction, m.NewReaction)
+}
+
+// MessageReactionCountUpdated object represents reaction changes on a message with anonymous reactions.
+type MessageReactionCountUpdated struct {
+	Chat      Chat            `json:"chat"`
+	MessageID int64           `json:"message_id"`
+	Date      UnixTime        `json:"date"`
+	Reactions []ReactionCount `json:"reactions"`
+}
+
+func diffReactions(old, new []ReactionType) (added, removed []ReactionType) {
+	oldSet := make(map[ReactionType]bool, len(old))
+	for _, r := range old {
+		oldSet[r] = true
+	}
+
+	newSet := make(map[ReactionType]bool, len(new))
+	for _, r := range new {
+		newSet[r] = true
+		if !oldSet[r] {
+			added = append(added, r)
+		}
+	}
+
+	for _, r := range old {
+		if !newSet[r] {
+			removed = append(removed, r)
+		}
+	}
+
+	return added, removed
+}
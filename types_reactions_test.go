@@ -0,0 +1,52 @@
+package micha
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageReactionUpdate(t *testing.T) {
+	data := `{
+		"update_id": 1,
+		"message_reaction": {
+			"chat": {"id": 1, "type": "private"},
+			"message_id": 5,
+			"user": {"id": 2, "first_name": "John"},
+			"date": 1700000000,
+			"old_reaction": [{"type": "emoji", "emoji": "👍"}, {"type": "paid"}],
+			"new_reaction": [{"type": "paid"}, {"type": "custom_emoji", "custom_emoji_id": "42"}]
+		}
+	}`
+
+	update := Update{}
+	require.Nil(t, json.Unmarshal([]byte(data), &update))
+	require.NotNil(t, update.MessageReaction)
+	require.Equal(t, int64(2), update.MessageReaction.User.ID)
+
+	added, removed := update.MessageReaction.Diff()
+	require.Equal(t, []ReactionType{NewCustomEmojiReaction("42")}, added)
+	require.Equal(t, []ReactionType{NewEmojiReaction("👍")}, removed)
+}
+
+func TestMessageReactionCountUpdate(t *testing.T) {
+	data := `{
+		"update_id": 1,
+		"message_reaction_count": {
+			"chat": {"id": -100, "type": "channel"},
+			"message_id": 5,
+			"date": 1700000000,
+			"reactions": [{"type": {"type": "emoji", "emoji": "🔥"}, "total_count": 3}]
+		}
+	}`
+
+	update := Update{}
+	require.Nil(t, json.Unmarshal([]byte(data), &update))
+	require.NotNil(t, update.MessageReactionCount)
+	require.Equal(t, []ReactionCount{{Type: NewEmojiReaction("🔥"), TotalCount: 3}}, update.MessageReactionCount.Reactions)
+
+	added, removed := diffReactions(nil, nil)
+	require.Nil(t, added)
+	require.Nil(t, removed)
+}
@@ -11,3 +11,11 @@ func TestReplyMarkup(t *testing.T) {
 	(ForceReply{}).itsReplyMarkup()
 	(ForceReply{}).itsReplyMarkup()
 }
+
+func TestInputMedia(t *testing.T) {
+	(InputMediaPhoto{}).itsInputMedia()
+	(InputMediaVideo{}).itsInputMedia()
+	(InputMediaAnimation{}).itsInputMedia()
+	(InputMediaAudio{}).itsInputMedia()
+	(InputMediaDocument{}).itsInputMedia()
+}
@@ -0,0 +1,205 @@
+package micha
+
+// UpdateKind - kind of update, the same as the name of its optional field (and allowed_updates value)
+type UpdateKind string
+
+const (
+	UPDATE_KIND_UNKNOWN                UpdateKind = ""
+	UPDATE_KIND_MESSAGE                UpdateKind = "message"
+	UPDATE_KIND_EDITED_MESSAGE         UpdateKind = "edited_message"
+	UPDATE_KIND_CHANNEL_POST           UpdateKind = "channel_post"
+	UPDATE_KIND_EDITED_CHANNEL_POST    UpdateKind = "edited_channel_post"
+	UPDATE_KIND_INLINE_QUERY           UpdateKind = "inline_query"
+	UPDATE_KIND_CHOSEN_INLINE_RESULT   UpdateKind = "chosen_inline_result"
+	UPDATE_KIND_CALLBACK_QUERY         UpdateKind = "callback_query"
+	UPDATE_KIND_SHIPPING_QUERY         UpdateKind = "shipping_query"
+	UPDATE_KIND_PRE_CHECKOUT_QUERY     UpdateKind = "pre_checkout_query"
+	UPDATE_KIND_POLL                   UpdateKind = "poll"
+	UPDATE_KIND_POLL_ANSWER            UpdateKind = "poll_answer"
+	UPDATE_KIND_MESSAGE_REACTION       UpdateKind = "message_reaction"
+	UPDATE_KIND_MESSAGE_REACTION_COUNT UpdateKind = "message_reaction_count"
+)
+
+// Values of allowed_updates for Start, LongPolling and SetWebhookOptions.AllowedUpdates
+const (
+	ALLOWED_UPDATE_MESSAGE                = string(UPDATE_KIND_MESSAGE)
+	ALLOWED_UPDATE_EDITED_MESSAGE         = string(UPDATE_KIND_EDITED_MESSAGE)
+	ALLOWED_UPDATE_CHANNEL_POST           = string(UPDATE_KIND_CHANNEL_POST)
+	ALLOWED_UPDATE_EDITED_CHANNEL_POST    = string(UPDATE_KIND_EDITED_CHANNEL_POST)
+	ALLOWED_UPDATE_INLINE_QUERY           = string(UPDATE_KIND_INLINE_QUERY)
+	ALLOWED_UPDATE_CHOSEN_INLINE_RESULT   = string(UPDATE_KIND_CHOSEN_INLINE_RESULT)
+	ALLOWED_UPDATE_CALLBACK_QUERY         = string(UPDATE_KIND_CALLBACK_QUERY)
+	ALLOWED_UPDATE_SHIPPING_QUERY         = string(UPDATE_KIND_SHIPPING_QUERY)
+	ALLOWED_UPDATE_PRE_CHECKOUT_QUERY     = string(UPDATE_KIND_PRE_CHECKOUT_QUERY)
+	ALLOWED_UPDATE_POLL                   = string(UPDATE_KIND_POLL)
+	ALLOWED_UPDATE_POLL_ANSWER            = string(UPDATE_KIND_POLL_ANSWER)
+	ALLOWED_UPDATE_MESSAGE_REACTION       = string(UPDATE_KIND_MESSAGE_REACTION)
+	ALLOWED_UPDATE_MESSAGE_REACTION_COUNT = string(UPDATE_KIND_MESSAGE_REACTION_COUNT)
+)
+
+// UpdateKinds - return all known update kinds.
+// Reactions are not delivered by default, use AllowedUpdates(UpdateKinds()...) to receive every kind.
+func UpdateKinds() []UpdateKind {
+	return []UpdateKind{
+		UPDATE_KIND_MESSAGE,
+		UPDATE_KIND_EDITED_MESSAGE,
+		UPDATE_KIND_CHANNEL_POST,
+		UPDATE_KIND_EDITED_CHANNEL_POST,
+		UPDATE_KIND_INLINE_QUERY,
+		UPDATE_KIND_CHOSEN_INLINE_RESULT,
+		UPDATE_KIND_CALLBACK_QUERY,
+		UPDATE_KIND_SHIPPING_QUERY,
+		UPDATE_KIND_PRE_CHECKOUT_QUERY,
+		UPDATE_KIND_POLL,
+		UPDATE_KIND_POLL_ANSWER,
+		UPDATE_KIND_MESSAGE_REACTION,
+		UPDATE_KIND_MESSAGE_REACTION_COUNT,
+	}
+}
+
+// AllowedUpdates - convert update kinds to allowed_updates values
+func AllowedUpdates(kinds ...UpdateKind) []string {
+	allowedUpdates := make([]string, len(kinds))
+	for i, kind := range kinds {
+		allowedUpdates[i] = string(kind)
+	}
+
+	return allowedUpdates
+}
+
+// Sender - sender of an update: a user, or a chat for channel posts,
+// messages of anonymous group administrators and anonymous reactions and poll answers.
+type Sender struct {
+	User *User
+	Chat *Chat
+}
+
+// Kind - return kind of update
+func (u Update) Kind() UpdateKind {
+	switch {
+	case u.Message != nil:
+		return UPDATE_KIND_MESSAGE
+	case u.EditedMessage != nil:
+		return UPDATE_KIND_EDITED_MESSAGE
+	case u.ChannelPost != nil:
+		return UPDATE_KIND_CHANNEL_POST
+	case u.EditedChannelPost != nil:
+		return UPDATE_KIND_EDITED_CHANNEL_POST
+	case u.InlineQuery != nil:
+		return UPDATE_KIND_INLINE_QUERY
+	case u.ChosenInlineResult != nil:
+		return UPDATE_KIND_CHOSEN_INLINE_RESULT
+	case u.CallbackQuery != nil:
+		return UPDATE_KIND_CALLBACK_QUERY
+	case u.ShippingQuery != nil:
+		return UPDATE_KIND_SHIPPING_QUERY
+	case u.PreCheckoutQuery != nil:
+		return UPDATE_KIND_PRE_CHECKOUT_QUERY
+	case u.Poll != nil:
+		return UPDATE_KIND_POLL
+	case u.PollAnswer != nil:
+		return UPDATE_KIND_POLL_ANSWER
+	case u.MessageReaction != nil:
+		return UPDATE_KIND_MESSAGE_REACTION
+	case u.MessageReactionCount != nil:
+		return UPDATE_KIND_MESSAGE_REACTION_COUNT
+	}
+
+	return UPDATE_KIND_UNKNOWN
+}
+
+// EffectiveMessage - return new or edited message, channel post or message with the callback button.
+// Nil for other updates and callback queries of inline messages.
+func (u Update) EffectiveMessage() *Message {
+	switch {
+	case u.Message != nil:
+		return u.Message
+	case u.EditedMessage != nil:
+		return u.EditedMessage
+	case u.ChannelPost != nil:
+		return u.ChannelPost
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.Message
+	}
+
+	return nil
+}
+
+// EffectiveChat - return chat the update belongs to, nil for inline queries, polls, payments etc.
+func (u Update) EffectiveChat() *Chat {
+	if message := u.EffectiveMessage(); message != nil {
+		return &message.Chat
+	}
+
+	switch {
+	case u.MessageReaction != nil:
+		return &u.MessageReaction.Chat
+	case u.MessageReactionCount != nil:
+		return &u.MessageReactionCount.Chat
+	}
+
+	return nil
+}
+
+// EffectiveUser - return user who sent the update.
+// Nil for channel posts, anonymous reactions and poll answers, polls and reaction counts.
+func (u Update) EffectiveUser() *User {
+	switch {
+	case u.Message != nil:
+		return u.Message.From
+	case u.EditedMessage != nil:
+		return u.EditedMessage.From
+	case u.ChannelPost != nil:
+		return u.ChannelPost.From
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost.From
+	case u.CallbackQuery != nil:
+		return &u.CallbackQuery.From
+	case u.InlineQuery != nil:
+		return &u.InlineQuery.From
+	case u.ChosenInlineResult != nil:
+		return &u.ChosenInlineResult.From
+	case u.ShippingQuery != nil:
+		return &u.ShippingQuery.From
+	case u.PreCheckoutQuery != nil:
+		return &u.PreCheckoutQuery.From
+	case u.PollAnswer != nil:
+		return u.PollAnswer.User
+	case u.MessageReaction != nil:
+		return u.MessageReaction.User
+	}
+
+	return nil
+}
+
+// EffectiveSender - return sender of the update.
+// Sender chat is preferred to user: messages of anonymous administrators have
+// the group as sender chat and a placeholder bot as user.
+func (u Update) EffectiveSender() *Sender {
+	var chat *Chat
+	switch {
+	case u.Message != nil:
+		chat = u.Message.SenderChat
+	case u.EditedMessage != nil:
+		chat = u.EditedMessage.SenderChat
+	case u.ChannelPost != nil:
+		chat = u.ChannelPost.SenderChat
+	case u.EditedChannelPost != nil:
+		chat = u.EditedChannelPost.SenderChat
+	case u.PollAnswer != nil:
+		chat = u.PollAnswer.VoterChat
+	case u.MessageReaction != nil:
+		chat = u.MessageReaction.ActorChat
+	}
+	if chat != nil {
+		return &Sender{Chat: chat}
+	}
+
+	if user := u.EffectiveUser(); user != nil {
+		return &Sender{User: user}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package micha
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeTestUpdate(t *testing.T, data string) Update {
+	update := Update{}
+	require.Nil(t, json.Unmarshal([]byte(data), &update))
+	return update
+}
+
+func TestUpdateKind(t *testing.T) {
+	require.Equal(t, UPDATE_KIND_UNKNOWN, Update{UpdateID: 1}.Kind())
+
+	for _, kind := range UpdateKinds() {
+		update := decodeTestUpdate(t, `{"update_id":1,"`+string(kind)+`":{}}`)
+		require.Equal(t, kind, update.Kind())
+	}
+
+	require.Equal(t, []string{"message", "callback_query"}, AllowedUpdates(UPDATE_KIND_MESSAGE, UPDATE_KIND_CALLBACK_QUERY))
+	require.Equal(t, "message_reaction", ALLOWED_UPDATE_MESSAGE_REACTION)
+	require.Len(t, AllowedUpdates(UpdateKinds()...), 13)
+}
+
+func TestUpdateEffective(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		update     string
+		messageID  int64
+		chatID     ChatID
+		userID     int64
+		senderUser int64
+		senderChat ChatID
+	}{
+		{
+			name:       "private message",
+			update:     `{"update_id":1,"message":{"message_id":5,"date":1,"from":{"id":2,"first_name":"A"},"chat":{"id":2,"type":"private"},"text":"hi"}}`,
+			messageID:  5,
+			chatID:     "2",
+			userID:     2,
+			senderUser: 2,
+		},
+		{
+			name:       "channel post has no sender user",
+			update:     `{"update_id":2,"channel_post":{"message_id":6,"date":1,"sender_chat":{"id":-1001,"type":"channel"},"chat":{"id":-1001,"type":"channel"},"text":"post"}}`,
+			messageID:  6,
+			chatID:     "-1001",
+			senderChat: "-1001",
+		},
+		{
+			name:       "anonymous group administrator",
+			update:     `{"update_id":3,"message":{"message_id":7,"date":1,"from":{"id":1087968824,"is_bot":true,"first_name":"Group"},"sender_chat":{"id":-1002,"type":"supergroup"},"chat":{"id":-1002,"type":"supergroup"},"text":"hi"}}`,
+			messageID:  7,
+			chatID:     "-1002",
+			userID:     1087968824,
+			senderChat: "-1002",
+		},
+		{
+			name:       "callback query",
+			update:     `{"update_id":4,"callback_query":{"id":"q","from":{"id":3,"first_name":"B"},"message":{"message_id":8,"date":1,"from":{"id":9,"is_bot":true,"first_name":"Bot"},"chat":{"id":3,"type":"private"}},"data":"x"}}`,
+			messageID:  8,
+			chatID:     "3",
+			userID:     3,
+			senderUser: 3,
+		},
+		{
+			name:       "anonymous reaction",
+			update:     `{"update_id":5,"message_reaction":{"chat":{"id":-1003,"type":"supergroup"},"message_id":1,"actor_chat":{"id":-1003,"type":"supergroup"},"date":1,"old_reaction":[],"new_reaction":[]}}`,
+			chatID:     "-1003",
+			senderChat: "-1003",
+		},
+		{
+			name:       "inline query",
+			update:     `{"update_id":6,"inline_query":{"id":"i","from":{"id":4,"first_name":"C"},"query":"q","offset":""}}`,
+			userID:     4,
+			senderUser: 4,
+		},
+		{
+			name:       "shipping query",
+			update:     `{"update_id":7,"shipping_query":{"id":"s","from":{"id":5,"first_name":"D"},"invoice_payload":"p","shipping_address":{"country_code":"US","state":"","city":"NY","street_line1":"1st","street_line2":"","post_code":"10001"}}}`,
+			userID:     5,
+			senderUser: 5,
+		},
+		{
+			name:       "pre-checkout query",
+			update:     `{"update_id":8,"pre_checkout_query":{"id":"p","from":{"id":6,"first_name":"E"},"currency":"USD","total_amount":100,"invoice_payload":"p"}}`,
+			userID:     6,
+			senderUser: 6,
+		},
+		{
+			name:   "poll",
+			update: `{"update_id":9,"poll":{"id":"1","question":"?","options":[]}}`,
+		},
+	} {
+		update := decodeTestUpdate(t, c.update)
+
+		message := update.EffectiveMessage()
+		if c.messageID == 0 {
+			require.Nil(t, message, c.name)
+		} else {
+			require.Equal(t, c.messageID, message.MessageID, c.name)
+		}
+
+		chat := update.EffectiveChat()
+		if c.chatID == "" {
+			require.Nil(t, chat, c.name)
+		} else {
+			require.Equal(t, c.chatID, chat.ID, c.name)
+		}
+
+		user := update.EffectiveUser()
+		if c.userID == 0 {
+			require.Nil(t, user, c.name)
+		} else {
+			require.Equal(t, c.userID, user.ID, c.name)
+		}
+
+		sender := update.EffectiveSender()
+		switch {
+		case c.senderChat != "":
+			require.Nil(t, sender.User, c.name)
+			require.Equal(t, c.senderChat, sender.Chat.ID, c.name)
+		case c.senderUser != 0:
+			require.Nil(t, sender.Chat, c.name)
+			require.Equal(t, c.senderUser, sender.User.ID, c.name)
+		default:
+			require.Nil(t, sender, c.name)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package micha
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// UpdateSource - source of updates for Bot.StartWith.
+// Long polling, webhook and replayer of saved updates implement it.
+type UpdateSource interface {
+	// Run - send updates to channel until source is exhausted or ctx is done
+	Run(ctx context.Context, updates chan<- Update) error
+}
+
+type longPolling struct {
+	bot            *Bot
+	allowedUpdates []string
+}
+
+// LongPolling - return source receiving updates with getUpdates
+func (bot *Bot) LongPolling(allowedUpdates ...string) UpdateSource {
+	return &longPolling{bot: bot, allowedUpdates: allowedUpdates}
+}
+
+func (p *longPolling) Run(ctx context.Context, updates chan<- Update) error {
+	for {
+		result, err := p.bot.getUpdates(p.bot.offset+1, p.allowedUpdates...)
+		if err != nil {
+			p.bot.logger.ErrorContext(ctx, "Get updates error", "error", err)
+			httpErr := HTTPError{}
+			if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusConflict {
+				// Webhook is set or another instance is polling
+				p.bot.cancelFunc()
+			}
+		}
+
+		for _, update := range result {
+			p.bot.offset = update.UpdateID
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+}
+
+// WebhookSource - http.Handler receiving updates sent by Telegram to the webhook.
+// Requests are answered after the update is taken by the consumer, so handler provides backpressure.
+type WebhookSource struct {
+	secretToken string
+	updates     chan Update
+	jsonCodec   JSONCodec
+}
+
+// WebhookOption - option of WebhookSource
+type WebhookOption func(*WebhookSource)
+
+// WithWebhookJSONCodec - decode updates with codec, defaults to encoding/json
+func WithWebhookJSONCodec(codec JSONCodec) WebhookOption {
+	return func(s *WebhookSource) {
+		s.jsonCodec = codec
+	}
+}
+
+// NewWebhookSource - create webhook source, requests without X-Telegram-Bot-Api-Secret-Token header
+// equal to secretToken are rejected (the check is disabled if secretToken is empty)
+func NewWebhookSource(secretToken string, opts ...WebhookOption) *WebhookSource {
+	s := &WebhookSource{
+		secretToken: secretToken,
+		updates:     make(chan Update),
+		jsonCodec:   stdJSONCodec{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WebhookSource - create webhook source decoding updates with the bot JSON codec (see NewWebhookSource)
+func (bot *Bot) WebhookSource(secretToken string, opts ...WebhookOption) *WebhookSource {
+	return NewWebhookSource(secretToken, append([]WebhookOption{WithWebhookJSONCodec(bot.jsonCodec)}, opts...)...)
+}
+
+func (s *WebhookSource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+	if s.secretToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.secretToken)) != 1 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	update := Update{}
+	if err := s.jsonCodec.Unmarshal(body, &update); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case s.updates <- update:
+	case <-r.Context().Done():
+		// Telegram will retry delivery
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	}
+}
+
+func (s *WebhookSource) Run(ctx context.Context, updates chan<- Update) error {
+	for {
+		select {
+		case update := <-s.updates:
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ReplayOption - option of UpdateReplayer
+type ReplayOption func(*UpdateReplayer)
+
+// WithReplaySpeed - replay updates with original intervals divided by factor
+// (2 is twice as fast as original). Zero factor replays as fast as possible.
+func WithReplaySpeed(factor float64) ReplayOption {
+	return func(r *UpdateReplayer) {
+		r.speed = factor
+	}
+}
+
+// WithReplayOriginalTiming - replay updates with original intervals
+func WithReplayOriginalTiming() ReplayOption {
+	return WithReplaySpeed(1)
+}
+
+// WithReplayJSONCodec - decode updates with codec, defaults to encoding/json
+func WithReplayJSONCodec(codec JSONCodec) ReplayOption {
+	return func(r *UpdateReplayer) {
+		r.jsonCodec = codec
+	}
+}
+
+// UpdateReplayer - source of updates saved as JSONL dump (one raw Update per line).
+// By default updates are replayed as fast as possible.
+// Intervals are taken from dates of messages, updates without date are sent immediately.
+type UpdateReplayer struct {
+	open      func() (io.ReadCloser, error)
+	speed     float64
+	sleep     func(ctx context.Context, d time.Duration) error
+	jsonCodec JSONCodec
+}
+
+// NewUpdateReplayer - create replayer reading updates from r
+func NewUpdateReplayer(r io.Reader, opts ...ReplayOption) *UpdateReplayer {
+	return newUpdateReplayer(func() (io.ReadCloser, error) {
+		return io.NopCloser(r), nil
+	}, opts)
+}
+
+// NewFileUpdateReplayer - create replayer reading updates from file, file is opened on every Run
+func NewFileUpdateReplayer(path string, opts ...ReplayOption) *UpdateReplayer {
+	return newUpdateReplayer(func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}, opts)
+}
+
+func newUpdateReplayer(open func() (io.ReadCloser, error), opts []ReplayOption) *UpdateReplayer {
+	r := &UpdateReplayer{
+		open:      open,
+		sleep:     sleepContext,
+		jsonCodec: stdJSONCodec{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run - send all updates from dump, returns nil when dump is exhausted
+func (r *UpdateReplayer) Run(ctx context.Context, updates chan<- Update) error {
+	reader, err := r.open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	var lastDate UnixTime
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		update := Update{}
+		if err := r.jsonCodec.Unmarshal(scanner.Bytes(), &update); err != nil {
+			return fmt.Errorf("update line %d: %w", line, err)
+		}
+
+		if date := updateDate(update); date > 0 {
+			if r.speed > 0 && lastDate > 0 && date > lastDate {
+				delay := time.Duration(float64(time.Duration(date-lastDate)*time.Second) / r.speed)
+				if err := r.sleep(ctx, delay); err != nil {
+					return err
+				}
+			}
+			lastDate = max(lastDate, date)
+		}
+
+		select {
+		case updates <- update:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return scanner.Err()
+}
+
+// updateDate - return unix time of update if it's known
+func updateDate(update Update) UnixTime {
+	for _, message := range []*Message{update.Message, update.ChannelPost} {
+		if message != nil {
+			return message.Date
+		}
+	}
+	for _, message := range []*Message{update.EditedMessage, update.EditedChannelPost} {
+		if message != nil {
+			return max(message.EditDate, message.Date)
+		}
+	}
+	if update.MessageReaction != nil {
+		return update.MessageReaction.Date
+	}
+
+	return 0
+}
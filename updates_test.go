@@ -0,0 +1,145 @@
+package micha
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testUpdatesDump = `{"update_id":1,"message":{"message_id":1,"date":1000,"chat":{"id":1,"type":"private"},"text":"a"}}
+{"update_id":2,"callback_query":{"id":"q","from":{"id":1,"first_name":"A"},"data":"x"}}
+
+{"update_id":3,"message":{"message_id":2,"date":1010,"chat":{"id":1,"type":"private"},"text":"b"}}
+{"update_id":4,"edited_message":{"message_id":2,"date":1010,"edit_date":1004,"chat":{"id":1,"type":"private"},"text":"c"}}
+{"update_id":5,"message":{"message_id":3,"date":1012,"chat":{"id":1,"type":"private"},"text":"d"}}
+`
+
+func collectUpdates(t *testing.T, source UpdateSource) ([]uint64, error) {
+	updates := make(chan Update)
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Run(context.Background(), updates)
+		close(updates)
+	}()
+
+	ids := []uint64{}
+	for update := range updates {
+		ids = append(ids, update.UpdateID)
+	}
+
+	return ids, <-done
+}
+
+func TestUpdateReplayer(t *testing.T) {
+	for _, c := range []struct {
+		options []ReplayOption
+		delays  []time.Duration
+	}{
+		{nil, []time.Duration{}},
+		{[]ReplayOption{WithReplayOriginalTiming()}, []time.Duration{10 * time.Second, 2 * time.Second}},
+		{[]ReplayOption{WithReplaySpeed(4)}, []time.Duration{2500 * time.Millisecond, 500 * time.Millisecond}},
+	} {
+		replayer := NewUpdateReplayer(strings.NewReader(testUpdatesDump), c.options...)
+		delays := []time.Duration{}
+		replayer.sleep = func(ctx context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}
+
+		ids, err := collectUpdates(t, replayer)
+		require.Nil(t, err)
+		require.Equal(t, []uint64{1, 2, 3, 4, 5}, ids)
+		require.Equal(t, c.delays, delays)
+	}
+}
+
+func TestUpdateReplayerErrors(t *testing.T) {
+	ids, err := collectUpdates(t, NewUpdateReplayer(strings.NewReader("{\"update_id\":1}\n{")))
+	require.Equal(t, []uint64{1}, ids)
+	require.EqualError(t, err, "update line 2: unexpected end of JSON input")
+
+	_, err = collectUpdates(t, NewFileUpdateReplayer(filepath.Join(t.TempDir(), "missing.jsonl")))
+	require.True(t, os.IsNotExist(err))
+
+	// Cancelled while waiting for original timing
+	replayer := NewUpdateReplayer(strings.NewReader(testUpdatesDump), WithReplayOriginalTiming())
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan Update, 10)
+	go func() {
+		<-updates
+		cancel()
+	}()
+	require.ErrorIs(t, replayer.Run(ctx, updates), context.Canceled)
+}
+
+func TestFileUpdateReplayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "updates.jsonl")
+	require.Nil(t, os.WriteFile(path, []byte(testUpdatesDump), 0o600))
+
+	bot := &Bot{
+		updates: make(chan Update),
+		Options: Options{logger: slog.Default()},
+	}
+	bot.ctx, bot.cancelFunc = context.WithCancel(context.Background())
+	go bot.StartWith(NewFileUpdateReplayer(path))
+
+	texts := []string{}
+	for update := range bot.Updates() {
+		if update.Message != nil {
+			texts = append(texts, update.Message.Text)
+		}
+	}
+	require.Equal(t, []string{"a", "b", "d"}, texts)
+}
+
+func TestWebhookSource(t *testing.T) {
+	source := NewWebhookSource("secret")
+	server := httptest.NewServer(source)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan Update)
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Run(ctx, updates)
+	}()
+
+	send := func(token, body string) int {
+		request, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+		require.Nil(t, err)
+		request.Header.Set("X-Telegram-Bot-Api-Secret-Token", token)
+
+		response, err := http.DefaultClient.Do(request)
+		require.Nil(t, err)
+		response.Body.Close()
+
+		return response.StatusCode
+	}
+
+	status := make(chan int, 1)
+	go func() {
+		status <- send("secret", `{"update_id":7}`)
+	}()
+	update := <-updates
+	require.Equal(t, uint64(7), update.UpdateID)
+	require.Equal(t, http.StatusOK, <-status)
+
+	require.Equal(t, http.StatusUnauthorized, send("wrong", `{"update_id":8}`))
+	require.Equal(t, http.StatusBadRequest, send("secret", `{`))
+
+	response, err := http.Get(server.URL)
+	require.Nil(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
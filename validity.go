@@ -3,15 +3,18 @@ package micha
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/url"
+	"slices"
 	"sort"
 	"strings"
 )
 
 var (
-	ErrInvalidHash = errors.New("invalid hash")
+	ErrInvalidHash     = errors.New("invalid hash")
+	ErrAuthDateExpired = errors.New("auth_date is expired")
 )
 
 // ValidateAuthCallback - https://core.telegram.org/widgets/login#checking-authorization
@@ -21,8 +24,8 @@ func ValidateAuthCallback(values url.Values, botToken string) error {
 	return validateHash(values, secret[:])
 }
 
-// ValidateWabAppData - https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app
-func ValidateWabAppData(values url.Values, botToken string) error {
+// ValidateWebAppData - https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app
+func ValidateWebAppData(values url.Values, botToken string) error {
 	hm := hmac.New(sha256.New, []byte("WebAppData"))
 	_, err := hm.Write([]byte(botToken))
 	if err != nil {
@@ -34,6 +37,13 @@ func ValidateWabAppData(values url.Values, botToken string) error {
 	return validateHash(values, secret)
 }
 
+// ValidateWabAppData - validate Web App data
+//
+// Deprecated: use ValidateWebAppData or ParseWebAppInitData
+func ValidateWabAppData(values url.Values, botToken string) error {
+	return ValidateWebAppData(values, botToken)
+}
+
 func validateHash(values url.Values, secret []byte) error {
 	hm := hmac.New(sha256.New, secret)
 	_, err := hm.Write([]byte(buildCheckString(values)))
@@ -41,17 +51,18 @@ func validateHash(values url.Values, secret []byte) error {
 		return err
 	}
 
-	if fmt.Sprintf("%x", hm.Sum(nil)) != values.Get("hash") {
+	hash, err := hex.DecodeString(values.Get("hash"))
+	if err != nil || !hmac.Equal(hm.Sum(nil), hash) {
 		return ErrInvalidHash
 	}
 
 	return nil
 }
 
-func buildCheckString(values url.Values) string {
+func buildCheckString(values url.Values, skip ...string) string {
 	keys := []string{}
 	for key := range values {
-		if key == "hash" {
+		if key == "hash" || slices.Contains(skip, key) {
 			continue
 		}
 		keys = append(keys, key)
@@ -1,8 +1,15 @@
 package micha
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -41,3 +48,68 @@ func TestValidateHash(t *testing.T) {
 	err = validateHash(values, []byte("222"))
 	require.NotNil(t, err)
 }
+
+func signTestLoginValues(values url.Values, botToken string) url.Values {
+	secret := sha256.Sum256([]byte(botToken))
+	hm := hmac.New(sha256.New, secret[:])
+	hm.Write([]byte(buildCheckString(values)))
+	values.Set("hash", hex.EncodeToString(hm.Sum(nil)))
+
+	return values
+}
+
+func TestParseLoginWidget(t *testing.T) {
+	authTime := time.Unix(time.Now().Add(-time.Minute).Unix(), 0)
+	values := signTestLoginValues(url.Values{
+		"id":         {"12807202"},
+		"first_name": {"John"},
+		"username":   {"doe"},
+		"photo_url":  {"https://t.me/i/userpic/320/a.jpg"},
+		"auth_date":  {strconv.FormatInt(authTime.Unix(), 10)},
+	}, "token")
+
+	user, err := ParseLoginWidget(values, "token", time.Hour)
+	require.Nil(t, err)
+	require.Equal(t, &LoginWidgetUser{
+		ID:        12807202,
+		FirstName: "John",
+		Username:  "doe",
+		PhotoURL:  "https://t.me/i/userpic/320/a.jpg",
+		AuthTime:  authTime,
+	}, user)
+
+	_, err = ParseLoginWidget(values, "token", time.Second)
+	require.ErrorIs(t, err, ErrAuthDateExpired)
+
+	_, err = ParseLoginWidget(values, "other", time.Hour)
+	require.ErrorIs(t, err, ErrInvalidHash)
+
+	values.Set("hash", "not hex")
+	_, err = ParseLoginWidget(values, "token", time.Hour)
+	require.ErrorIs(t, err, ErrInvalidHash)
+}
+
+func TestLoginWidgetMiddleware(t *testing.T) {
+	handler := LoginWidgetMiddleware("token", time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := LoginWidgetUserFromContext(r.Context())
+		require.True(t, ok)
+		w.Write([]byte(user.Username))
+	}))
+
+	values := signTestLoginValues(url.Values{
+		"id":         {"1"},
+		"first_name": {"John"},
+		"username":   {"doe"},
+		"auth_date":  {strconv.FormatInt(time.Now().Unix(), 10)},
+	}, "token")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/login?"+values.Encode(), nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "doe", recorder.Body.String())
+
+	values.Set("username", "admin")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/login?"+values.Encode(), nil))
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -0,0 +1,207 @@
+package micha
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Telegram public keys for third-party validation of Web App data
+	TELEGRAM_PUBLIC_KEY_PRODUCTION = "e7bf03a2fa4602af4580703d88dda5bb59f32ed8b02a56c187fe7d34caed242d"
+	TELEGRAM_PUBLIC_KEY_TEST       = "40055058a4ee38156a06562e52eece92a771bcd8346a8c4615cb7376eddf72ec"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// WebAppUser object contains the data of the Mini App user.
+type WebAppUser struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+
+	// Optional
+	IsBot                 bool   `json:"is_bot,omitempty"`
+	LastName              string `json:"last_name,omitempty"`
+	Username              string `json:"username,omitempty"`
+	LanguageCode          string `json:"language_code,omitempty"`
+	IsPremium             bool   `json:"is_premium,omitempty"`
+	AddedToAttachmentMenu bool   `json:"added_to_attachment_menu,omitempty"`
+	AllowsWriteToPm       bool   `json:"allows_write_to_pm,omitempty"`
+	PhotoURL              string `json:"photo_url,omitempty"`
+}
+
+// WebAppChat object represents a chat.
+type WebAppChat struct {
+	ID    int64    `json:"id"`
+	Type  ChatType `json:"type"`
+	Title string   `json:"title"`
+
+	// Optional
+	Username string `json:"username,omitempty"`
+	PhotoURL string `json:"photo_url,omitempty"`
+}
+
+// WebAppInitData object contains data that is transferred to the Mini App when it is opened.
+type WebAppInitData struct {
+	AuthDate UnixTime `json:"auth_date"`
+	Hash     string   `json:"hash"`
+
+	// Optional
+	QueryID      string      `json:"query_id,omitempty"`
+	User         *WebAppUser `json:"user,omitempty"`
+	Receiver     *WebAppUser `json:"receiver,omitempty"`
+	Chat         *WebAppChat `json:"chat,omitempty"`
+	ChatType     string      `json:"chat_type,omitempty"`
+	ChatInstance string      `json:"chat_instance,omitempty"`
+	StartParam   string      `json:"start_param,omitempty"`
+	CanSendAfter Seconds     `json:"can_send_after,omitempty"`
+	Signature    string      `json:"signature,omitempty"`
+}
+
+// SentWebAppMessage describes an inline message sent by a Web App on behalf of a user.
+type SentWebAppMessage struct {
+	// Optional
+	InlineMessageID string `json:"inline_message_id,omitempty"`
+}
+
+// PreparedInlineMessage describes an inline message to be sent by a user of a Mini App.
+type PreparedInlineMessage struct {
+	ID             string   `json:"id"`
+	ExpirationDate UnixTime `json:"expiration_date"`
+}
+
+// ParseWebAppInitData - validate Web App init data (Telegram.WebApp.initData) with bot token and parse it.
+// Data older than maxAge is rejected, zero maxAge disables the check.
+func ParseWebAppInitData(raw, botToken string, maxAge time.Duration) (*WebAppInitData, error) {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateWebAppData(values, botToken); err != nil {
+		return nil, err
+	}
+
+	return decodeWebAppInitData(values, maxAge)
+}
+
+// ParseWebAppInitDataThirdParty - validate Web App init data signature with Telegram public key and parse it.
+// Can be used by third parties which don't have the bot token.
+// publicKey is TELEGRAM_PUBLIC_KEY_PRODUCTION or TELEGRAM_PUBLIC_KEY_TEST.
+func ParseWebAppInitDataThirdParty(raw string, botID int64, publicKey string, maxAge time.Duration) (*WebAppInitData, error) {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(values.Get("signature"), "="))
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	checkString := fmt.Sprintf("%d:WebAppData\n%s", botID, buildCheckString(values, "signature"))
+	if !ed25519.Verify(key, []byte(checkString), signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	return decodeWebAppInitData(values, maxAge)
+}
+
+func decodeWebAppInitData(values url.Values, maxAge time.Duration) (*WebAppInitData, error) {
+	data := &WebAppInitData{
+		Hash:         values.Get("hash"),
+		QueryID:      values.Get("query_id"),
+		ChatType:     values.Get("chat_type"),
+		ChatInstance: values.Get("chat_instance"),
+		StartParam:   values.Get("start_param"),
+		Signature:    values.Get("signature"),
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth_date: %w", err)
+	}
+	data.AuthDate = UnixTime(authDate)
+	if maxAge > 0 && time.Since(data.AuthDate.Time()) > maxAge {
+		return nil, ErrAuthDateExpired
+	}
+
+	if v := values.Get("can_send_after"); v != "" {
+		canSendAfter, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid can_send_after: %w", err)
+		}
+		data.CanSendAfter = Seconds(canSendAfter)
+	}
+
+	for key, target := range map[string]interface{}{
+		"user":     &data.User,
+		"receiver": &data.Receiver,
+		"chat":     &data.Chat,
+	} {
+		if v := values.Get(key); v != "" {
+			if err := json.Unmarshal([]byte(v), target); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", key, err)
+			}
+		}
+	}
+
+	return data, nil
+}
+
+type webAppContextKey struct{}
+
+// WebAppMiddleware - net/http middleware authenticating Mini App requests.
+// Init data is taken from "Authorization: tma <init data>" header.
+// Requests with invalid or expired data are rejected with 401 status.
+func WebAppMiddleware(botToken string, maxAge time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, raw, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !strings.EqualFold(scheme, "tma") {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			data, err := ParseWebAppInitData(raw, botToken, maxAge)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), webAppContextKey{}, data)))
+		})
+	}
+}
+
+// WebAppInitDataFromContext - return init data stored by WebAppMiddleware
+func WebAppInitDataFromContext(ctx context.Context) (*WebAppInitData, bool) {
+	data, ok := ctx.Value(webAppContextKey{}).(*WebAppInitData)
+	return data, ok
+}
+
+// WebAppUserFromContext - return user of init data stored by WebAppMiddleware
+func WebAppUserFromContext(ctx context.Context) (*WebAppUser, bool) {
+	data, ok := WebAppInitDataFromContext(ctx)
+	if !ok || data.User == nil {
+		return nil, false
+	}
+
+	return data.User, true
+}
@@ -0,0 +1,127 @@
+package micha
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testWebAppValues(authDate time.Time) url.Values {
+	return url.Values{
+		"query_id":       {"AAHdF6IQAAAAAN0XohDhrOrc"},
+		"user":           {`{"id":279058397,"first_name":"Vladislav","username":"vdkfrost","language_code":"ru","is_premium":true,"allows_write_to_pm":true}`},
+		"receiver":       {`{"id":42,"first_name":"Bob"}`},
+		"chat":           {`{"id":-100,"type":"supergroup","title":"Group"}`},
+		"chat_type":      {"supergroup"},
+		"chat_instance":  {"-123"},
+		"start_param":    {"ref"},
+		"can_send_after": {"10"},
+		"auth_date":      {strconv.FormatInt(authDate.Unix(), 10)},
+	}
+}
+
+func signTestWebAppValues(values url.Values, botToken string) string {
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+
+	hm := hmac.New(sha256.New, secret.Sum(nil))
+	hm.Write([]byte(buildCheckString(values)))
+	values.Set("hash", hex.EncodeToString(hm.Sum(nil)))
+
+	return values.Encode()
+}
+
+func TestParseWebAppInitData(t *testing.T) {
+	authDate := time.Now().Add(-time.Minute)
+	raw := signTestWebAppValues(testWebAppValues(authDate), "token")
+
+	data, err := ParseWebAppInitData(raw, "token", time.Hour)
+	require.Nil(t, err)
+	require.Equal(t, "AAHdF6IQAAAAAN0XohDhrOrc", data.QueryID)
+	require.Equal(t, authDate.Unix(), data.AuthDate.Time().Unix())
+	require.Equal(t, &WebAppUser{
+		ID:              279058397,
+		FirstName:       "Vladislav",
+		Username:        "vdkfrost",
+		LanguageCode:    "ru",
+		IsPremium:       true,
+		AllowsWriteToPm: true,
+	}, data.User)
+	require.Equal(t, &WebAppUser{ID: 42, FirstName: "Bob"}, data.Receiver)
+	require.Equal(t, &WebAppChat{ID: -100, Type: CHAT_TYPE_SUPERGROUP, Title: "Group"}, data.Chat)
+	require.Equal(t, "supergroup", data.ChatType)
+	require.Equal(t, "-123", data.ChatInstance)
+	require.Equal(t, "ref", data.StartParam)
+	require.Equal(t, Seconds(10), data.CanSendAfter)
+
+	_, err = ParseWebAppInitData(raw, "other", time.Hour)
+	require.ErrorIs(t, err, ErrInvalidHash)
+
+	_, err = ParseWebAppInitData(raw, "token", time.Second)
+	require.ErrorIs(t, err, ErrAuthDateExpired)
+
+	// Zero max age disables expiration check
+	_, err = ParseWebAppInitData(raw, "token", 0)
+	require.Nil(t, err)
+}
+
+func TestParseWebAppInitDataThirdParty(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	values := testWebAppValues(time.Now())
+	values.Set("hash", "ignored")
+	checkString := "12345:WebAppData\n" + buildCheckString(values)
+	values.Set("signature", base64.RawURLEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(checkString))))
+
+	data, err := ParseWebAppInitDataThirdParty(values.Encode(), 12345, hex.EncodeToString(publicKey), time.Hour)
+	require.Nil(t, err)
+	require.Equal(t, int64(279058397), data.User.ID)
+
+	_, err = ParseWebAppInitDataThirdParty(values.Encode(), 54321, hex.EncodeToString(publicKey), time.Hour)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = ParseWebAppInitDataThirdParty(values.Encode(), 12345, TELEGRAM_PUBLIC_KEY_PRODUCTION, time.Hour)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestWebAppMiddleware(t *testing.T) {
+	handler := WebAppMiddleware("token", time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := WebAppUserFromContext(r.Context())
+		require.True(t, ok)
+		w.Write([]byte(strconv.FormatInt(user.ID, 10)))
+	}))
+
+	raw := signTestWebAppValues(testWebAppValues(time.Now()), "token")
+	for _, c := range []struct {
+		authorization string
+		status        int
+		body          string
+	}{
+		{"tma " + raw, http.StatusOK, "279058397"},
+		{"Bearer " + raw, http.StatusUnauthorized, ""},
+		{"tma " + raw + "&x=1", http.StatusUnauthorized, ""},
+		{"", http.StatusUnauthorized, ""},
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("Authorization", c.authorization)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		require.Equal(t, c.status, recorder.Code)
+		if c.body != "" {
+			require.Equal(t, c.body, recorder.Body.String())
+		}
+	}
+}